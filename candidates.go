@@ -0,0 +1,136 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type CandidatesRunner func(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, namespace, typ string, opts RunOptions) ([]Reference, error)
+
+// serviceCandidates lists the LoadBalancer Services that have a Load Balancer
+func serviceCandidates(ctx context.Context, cp CloudPather, namespace string) ([]string, error) {
+	services, err := cp.Clients().Clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, svc := range services.Items {
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer && svc.Annotations[lbaasAnnotation] != "" {
+			names = append(names, svc.Name)
+		}
+	}
+	return names, nil
+}
+
+// nodeCandidates lists the nodes running on Droplets
+func nodeCandidates(ctx context.Context, cp CloudPather, namespace string) ([]string, error) {
+	nodes, err := cp.Clients().Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, node := range nodes.Items {
+		if strings.HasPrefix(node.Spec.ProviderID, nodeIDPrefix) {
+			names = append(names, node.Name)
+		}
+	}
+	return names, nil
+}
+
+// claimCandidates lists the bound claims backed by DigitalOcean Volumes
+func claimCandidates(ctx context.Context, cp CloudPather, namespace string) ([]string, error) {
+	clientset := cp.Clients().Clientset
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, pvc := range pvcs.Items {
+		if pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+		if pvc.Spec.VolumeName == "" {
+			class := pvc.Spec.StorageClassName
+			if class != nil && isDOStorageClass(*class) {
+				names = append(names, pvc.Name)
+			}
+			continue
+		}
+		pv, err := clientset.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		if _, err := volumeLink(pv); err == nil {
+			names = append(names, pvc.Name)
+		}
+	}
+	return names, nil
+}
+
+// Candidates returns references to the objects of typ that are backed by
+// DigitalOcean resources, sorted by name, in the namespace of the client for
+// namespaced types. ErrMissingArgument is returned for types whose resolver
+// cannot list candidates.
+func (c *Client) Candidates(ctx context.Context, typ string) ([]Reference, error) {
+	resolver, err := resolvers.resolverFor(typ, c.mapper)
+	if err != nil {
+		return nil, err
+	}
+	if resolver == nil {
+		return nil, fmt.Errorf("unknown type %s", typ)
+	}
+
+	if resolver.Candidates == nil {
+		return nil, ErrMissingArgument
+	}
+
+	namespace := c.opts.Namespace
+	if resolver.Scope == ScopeCluster {
+		namespace = ""
+	}
+
+	names, err := resolver.Candidates(ctx, c.cp, namespace)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	refs := make([]Reference, 0, len(names))
+	for _, name := range names {
+		refs = append(refs, Reference{Namespace: namespace, Type: typ, Name: name})
+	}
+	return refs, nil
+}
+
+// Candidates returns references to the objects of typ that are backed by
+// DigitalOcean resources, for the user to choose from
+func Candidates(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, namespace, typ string, opts RunOptions) ([]Reference, error) {
+	client, _, err := clientFor(writer, kubeConfig, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+	return client.Candidates(ctx, typ)
+}
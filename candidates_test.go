@@ -0,0 +1,118 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClient_Candidates(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+	blockStorage := storageClassName
+	xfsStorage := storageClassName + "-xfs"
+	otherStorage := "other"
+
+	cp := newFakeDOCloudPather()
+	cp.clientset.CoreV1().Services(namespace).Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: map[string]string{lbaasAnnotation: "lb-id"}},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Services(namespace).Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Services(namespace).Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "internal"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Nodes().Create(ctx, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool-2"},
+		Spec:       corev1.NodeSpec{ProviderID: nodeIDPrefix + "2"},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Nodes().Create(ctx, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool-1"},
+		Spec:       corev1.NodeSpec{ProviderID: nodeIDPrefix + "1"},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Nodes().Create(ctx, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "kind-worker"},
+		Spec:       corev1.NodeSpec{ProviderID: "kind://docker/kind/kind-worker"},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data"},
+		Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &blockStorage},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "xfs"},
+		Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &xfsStorage, VolumeName: "pv-xfs"},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumes().Create(ctx, &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-xfs"},
+		Spec: corev1.PersistentVolumeSpec{
+			StorageClassName: xfsStorage,
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: csiDriverName, VolumeHandle: "vol-xfs"},
+			},
+		},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "unbound"},
+		Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &blockStorage},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "nfs"},
+		Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &otherStorage},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}, metav1.CreateOptions{})
+
+	client := newClient(cp, nil, ClientOptions{Namespace: namespace})
+	tests := []struct {
+		typ       string
+		namespace string
+		want      []string
+		wantErr   error
+	}{
+		{typ: "svc", namespace: namespace, want: []string{"web"}},
+		{typ: "svc", namespace: "other", want: nil},
+		{typ: "nodes", want: []string{"pool-1", "pool-2"}},
+		{typ: "pvc", namespace: namespace, want: []string{"data", "xfs"}},
+		{typ: "pod", namespace: namespace, wantErr: ErrMissingArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ+"/"+tt.namespace, func(t *testing.T) {
+			client.opts.Namespace = tt.namespace
+			refs, err := client.Candidates(ctx, tt.typ)
+			if err != tt.wantErr {
+				t.Errorf("Client.Candidates() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var got []string
+			for _, ref := range refs {
+				got = append(got, ref.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.Candidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// Logger receives diagnostics about how references are resolved. A *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+type discardLogger struct{}
+
+func (discardLogger) Printf(format string, v ...interface{}) {}
+
+// ClientOptions configure a Client. Their zero values are usable.
+type ClientOptions struct {
+	// Namespace is used for references that do not have one. Defaults to "default".
+	Namespace string
+	// Output receives progress messages, such as the objects being opened. Discarded by default.
+	Output io.Writer
+	// CloudBase is the control panel URL links are built on. Defaults to DefaultCloudBase.
+	CloudBase string
+	// Logger receives diagnostics. Discarded by default.
+	Logger Logger
+	// Options tweak which page a resource resolves to
+	Options Options
+}
+
+// Client resolves references to the DigitalOcean resources backing them
+type Client struct {
+	cp            *DOCloudPather
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper
+	opts          ClientOptions
+}
+
+// NewClient returns a Client for the DOKS cluster clientConfig points at.
+// clientset must talk to the same cluster; it is used for discovery and to read objects.
+func NewClient(clientset kubernetes.Interface, clientConfig *restclient.Config, opts ClientOptions) (*Client, error) {
+	cp, err := NewDOCloudPather(clientset, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(cp, newRESTMapper(clientset.Discovery()), opts), nil
+}
+
+func newClient(cp *DOCloudPather, mapper meta.RESTMapper, opts ClientOptions) *Client {
+	if opts.Namespace == "" {
+		opts.Namespace = metav1.NamespaceDefault
+	}
+	if opts.Output == nil {
+		opts.Output = ioutil.Discard
+	}
+	if opts.CloudBase == "" {
+		opts.CloudBase = DefaultCloudBase
+	}
+	if !strings.HasSuffix(opts.CloudBase, "/") {
+		opts.CloudBase += "/"
+	}
+	if opts.Logger == nil {
+		opts.Logger = discardLogger{}
+	}
+
+	return &Client{
+		cp:            cp,
+		dynamicClient: cp.dynamicClient,
+		mapper:        mapper,
+		opts:          opts,
+	}
+}
+
+// Resolve returns the links of the object ref names. When a selector or all
+// namespaces are set in the options, a reference without a name resolves to
+// every matching object of its type, each with its own Result.
+func (c *Client) Resolve(ctx context.Context, ref Reference) []Result {
+	if !c.opts.Options.Bulk() {
+		return []Result{c.resolve(ctx, ref)}
+	}
+
+	if ref.Name != "" {
+		return []Result{{Reference: ref, Err: fmt.Errorf("a name cannot be provided when a selector or all namespaces are used")}}
+	}
+
+	targets, err := listReferences(ctx, c.dynamicClient, c.mapper, ref.Type, c.namespace(ref), c.opts.Options)
+	if err != nil {
+		return []Result{{Reference: ref, Err: err}}
+	}
+	if len(targets) == 0 {
+		fmt.Fprintf(c.opts.Output, "no %s found matching the selectors\n", ref.Type)
+	}
+	c.opts.Logger.Printf("%s matched %d objects", ref.Type, len(targets))
+
+	results := make([]Result, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, c.resolve(ctx, target))
+	}
+	return results
+}
+
+func (c *Client) namespace(ref Reference) string {
+	if ref.Namespace != "" {
+		return ref.Namespace
+	}
+	return c.opts.Namespace
+}
+
+func (c *Client) resolve(ctx context.Context, ref Reference) Result {
+	result := resolve(ctx, c.opts.Output, c.cp, c.mapper, c.namespace(ref), ref, c.opts.Options)
+	if result.Kind != "" {
+		c.opts.Logger.Printf("resolved %s as kind %s", ref, result.Kind)
+	}
+
+	for i, link := range result.Links {
+		result.Links[i] = link.rebased(c.opts.CloudBase)
+	}
+	return result
+}
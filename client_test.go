@@ -0,0 +1,109 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+)
+
+func TestClient_Resolve(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       corev1.NodeSpec{ProviderID: nodeIDPrefix + "droplet-id"},
+	})
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "nodes", SingularName: "node", Kind: "Node", ShortNames: []string{"no"}},
+			},
+		},
+	}
+	clientConfig := &restclient.Config{Host: fmt.Sprintf("https://cluster-id%s", hostnameSuffix)}
+
+	tests := []struct {
+		name      string
+		opts      ClientOptions
+		ref       Reference
+		wantURL   string
+		wantErr   bool
+		wantKind  string
+		wantWrite string
+	}{
+		{
+			name:      "default cloud base",
+			ref:       Reference{Type: "no", Name: "node-1"},
+			wantURL:   DefaultCloudBase + "droplets/droplet-id",
+			wantKind:  "Node",
+			wantWrite: "opening no node-1 (namespace default)\n",
+		},
+		{
+			name:      "custom cloud base and page",
+			opts:      ClientOptions{CloudBase: "https://cloud.example.com", Options: Options{Page: "insights"}},
+			ref:       Reference{Type: "cluster"},
+			wantURL:   "https://cloud.example.com/kubernetes/clusters/cluster-id/insights",
+			wantKind:  "Cluster",
+			wantWrite: "opening cluster  (namespace default)\n",
+		},
+		{
+			name:      "name with a selector",
+			opts:      ClientOptions{Options: Options{LabelSelector: "app=web"}},
+			ref:       Reference{Type: "no", Name: "node-1"},
+			wantErr:   true,
+			wantWrite: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			tt.opts.Output = output
+
+			client, err := NewClient(clientset, clientConfig, tt.opts)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			results := client.Resolve(context.TODO(), tt.ref)
+			if len(results) != 1 {
+				t.Fatalf("Client.Resolve() returned %d results, want 1", len(results))
+			}
+			result := results[0]
+			if (result.Err != nil) != tt.wantErr {
+				t.Errorf("Client.Resolve() error = %v, wantErr %v", result.Err, tt.wantErr)
+				return
+			}
+			if output.String() != tt.wantWrite {
+				t.Errorf("Client.Resolve() output = %q, want %q", output.String(), tt.wantWrite)
+			}
+			if tt.wantErr {
+				return
+			}
+			if result.Kind != tt.wantKind {
+				t.Errorf("Client.Resolve() kind = %v, want %v", result.Kind, tt.wantKind)
+			}
+			if len(result.Links) != 1 || result.Links[0].URL != tt.wantURL {
+				t.Errorf("Client.Resolve() links = %+v, want %v", result.Links, tt.wantURL)
+			}
+		})
+	}
+}
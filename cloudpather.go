@@ -16,39 +16,116 @@ package kubectldoweb
 import (
 	"context"
 	"fmt"
-	"io"
 	"net/url"
+	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 )
 
-// CloudPather returns cloud.digitalocean.com paths for K8s resources
+// CloudPather returns cloud.digitalocean.com links for K8s resources
 type CloudPather interface {
-	Cluster(context.Context) (string, error)
-	Node(context.Context, string) (string, error)
-	Service(context.Context, string, string) (string, error)
-	PersistentVolume(context.Context, string) (string, error)
-	PersistentVolumeClaim(context.Context, string, string) (string, error)
+	Cluster(context.Context) (Link, error)
+	Node(context.Context, string) (Link, error)
+	NodePool(context.Context, string) ([]Link, error)
+	PoolOfNode(context.Context, string) (Link, error)
+	Service(context.Context, string, string) (Link, error)
+	PersistentVolume(context.Context, string) (Link, error)
+	PersistentVolumeClaim(context.Context, string, string) (Link, error)
+	Pod(context.Context, string, string) ([]Link, error)
+	Workload(context.Context, string, string, string) ([]Link, error)
+	Ingress(context.Context, string, string) (Link, error)
+	Gateway(context.Context, string, string) (Link, error)
+	HTTPRoute(context.Context, string, string) ([]Link, error)
+	Clients() Clients
+}
+
+// Clients gives resolvers registered outside this package access to the cluster
+type Clients struct {
+	Clientset     kubernetes.Interface
+	DynamicClient dynamic.Interface
+	// ClusterID is empty when the cluster is not a DOKS cluster
+	ClusterID string
 }
 
 const nodeIDPrefix = "digitalocean://"
 const lbaasAnnotation = "kubernetes.digitalocean.com/load-balancer-id"
 const hostnameSuffix = ".k8s.ondigitalocean.com"
+const nodePoolIDLabel = "doks.digitalocean.com/node-pool-id"
+const nodePoolNameLabel = "doks.digitalocean.com/node-pool"
 const storageClassName = "do-block-storage"
+const csiDriverName = "dobs.csi.digitalocean.com"
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+const networkingGroup = "networking.k8s.io"
+const appNameLabel = "app.kubernetes.io/name"
+
+// networkingVersions are tried in order when reading Ingresses and IngressClasses,
+// as clusters from Kubernetes 1.22 on no longer serve v1beta1
+var networkingVersions = []string{"v1", "v1beta1"}
+
+// workload kinds accepted by DOCloudPather.Workload
+const (
+	kindDeployment  = "Deployment"
+	kindStatefulSet = "StatefulSet"
+	kindDaemonSet   = "DaemonSet"
+	kindReplicaSet  = "ReplicaSet"
+	kindJob         = "Job"
+)
 
 type DOCloudPather struct {
-	clientConfig *restclient.Config
-	clientset    kubernetes.Interface
-	output       io.Writer
+	clientConfig  *restclient.Config
+	clientset     kubernetes.Interface
+	dynamicClient dynamic.Interface
 }
 
 var _ CloudPather = &DOCloudPather{}
 
-func (cp *DOCloudPather) Cluster(ctx context.Context) (string, error) {
+// NewDOCloudPather returns a CloudPather for the DOKS cluster clientConfig points at
+func NewDOCloudPather(clientset kubernetes.Interface, clientConfig *restclient.Config) (*DOCloudPather, error) {
+	dynamicClient, err := dynamic.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DOCloudPather{
+		clientConfig:  clientConfig,
+		clientset:     clientset,
+		dynamicClient: dynamicClient,
+	}, nil
+}
+
+func (cp *DOCloudPather) Cluster(ctx context.Context) (Link, error) {
+	id, err := cp.clusterID()
+	if err != nil {
+		return Link{}, err
+	}
+
+	return newLink(ResourceKubernetesCluster, id, fmt.Sprintf("Kubernetes cluster %s", id), fmt.Sprintf("kubernetes/clusters/%s", id)), nil
+}
+
+// Clients returns the clients objects are read with and the ID of their cluster
+func (cp *DOCloudPather) Clients() Clients {
+	clusterID, _ := cp.clusterID()
+	return Clients{
+		Clientset:     cp.clientset,
+		DynamicClient: cp.dynamicClient,
+		ClusterID:     clusterID,
+	}
+}
+
+func (cp *DOCloudPather) clusterID() (string, error) {
+	if cp.clientConfig == nil || cp.clientConfig.Host == "" {
+		return "", fmt.Errorf("the cluster ID is unknown")
+	}
+
 	endpoint, err := url.Parse(cp.clientConfig.Host)
 	if err != nil {
 		return "", err
@@ -57,78 +134,468 @@ func (cp *DOCloudPather) Cluster(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("the cluster does not seem to be a DOKS cluster")
 	}
 
-	id := strings.TrimSuffix(endpoint.Host, hostnameSuffix)
-	return fmt.Sprintf("kubernetes/clusters/%s", id), nil
+	return strings.TrimSuffix(endpoint.Host, hostnameSuffix), nil
 }
 
-func (cp *DOCloudPather) Node(ctx context.Context, name string) (string, error) {
+func (cp *DOCloudPather) Node(ctx context.Context, name string) (Link, error) {
 	node, err := cp.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return Link{}, err
 	}
 
+	return nodeLink(node)
+}
+
+func nodeLink(node *corev1.Node) (Link, error) {
 	if !strings.HasPrefix(node.Spec.ProviderID, nodeIDPrefix) {
-		return "", fmt.Errorf("node %s is not a DigitalOcean-provisioned node", name)
+		return Link{}, fmt.Errorf("node %s is not a DigitalOcean-provisioned node", node.Name)
 	}
 
 	id := strings.TrimPrefix(node.Spec.ProviderID, nodeIDPrefix)
-	return fmt.Sprintf("droplets/%s", id), nil
+	link := newLink(ResourceDroplet, id, fmt.Sprintf("Droplet %s (node %s)", id, node.Name), fmt.Sprintf("droplets/%s", id))
+	link.Region = nodeRegion(node)
+	return link, nil
+}
+
+// NodePool returns the link of a DOKS node pool, given its name or ID. When no
+// name is given, the links of every node pool are returned.
+func (cp *DOCloudPather) NodePool(ctx context.Context, name string) ([]Link, error) {
+	clusterID, err := cp.clusterID()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := cp.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: nodePoolIDLabel})
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	poolNames := map[string]string{}
+	poolNodes := map[string]int{}
+	poolRegions := map[string]string{}
+	for i, node := range nodes.Items {
+		id := node.Labels[nodePoolIDLabel]
+		if _, ok := poolNames[id]; !ok {
+			ids = append(ids, id)
+		}
+		poolNames[id] = node.Labels[nodePoolNameLabel]
+		poolNodes[id]++
+		poolRegions[id] = nodeRegion(&nodes.Items[i])
+	}
+	sort.Slice(ids, func(i, j int) bool { return poolNames[ids[i]] < poolNames[ids[j]] })
+
+	var links []Link
+	for _, id := range ids {
+		if name != "" && name != id && name != poolNames[id] {
+			continue
+		}
+
+		link := poolLink(clusterID, id, fmt.Sprintf("Node pool %s (%d nodes)", poolNames[id], poolNodes[id]))
+		link.Region = poolRegions[id]
+		links = append(links, link)
+	}
+
+	if len(links) == 0 {
+		if name == "" {
+			return nil, fmt.Errorf("no nodes labeled with %s were found, the cluster does not seem to have DOKS node pools", nodePoolIDLabel)
+		}
+		return nil, fmt.Errorf("node pool %s was not found on any of the cluster's nodes", name)
+	}
+
+	return links, nil
 }
 
-func (cp *DOCloudPather) Service(ctx context.Context, namespace, name string) (string, error) {
+// PoolOfNode returns the link of the DOKS node pool a node belongs to
+func (cp *DOCloudPather) PoolOfNode(ctx context.Context, name string) (Link, error) {
+	node, err := cp.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return Link{}, err
+	}
+
+	clusterID, err := cp.clusterID()
+	if err != nil {
+		return Link{}, err
+	}
+
+	return nodePoolLink(clusterID, node)
+}
+
+func nodePoolLink(clusterID string, node *corev1.Node) (Link, error) {
+	id, ok := node.Labels[nodePoolIDLabel]
+	if !ok {
+		return Link{}, fmt.Errorf("label %s not found on node %s, it is not part of a DOKS node pool", nodePoolIDLabel, node.Name)
+	}
+
+	link := poolLink(clusterID, id, fmt.Sprintf("Node pool %s (node %s)", node.Labels[nodePoolNameLabel], node.Name))
+	link.Region = nodeRegion(node)
+	return link, nil
+}
+
+func poolLink(clusterID, id, title string) Link {
+	return newLink(ResourceNodePool, id, title, fmt.Sprintf("kubernetes/clusters/%s/nodepools/%s", clusterID, id))
+}
+
+func (cp *DOCloudPather) Service(ctx context.Context, namespace, name string) (Link, error) {
 	svc, err := cp.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return Link{}, err
 	}
 
+	return serviceLink(svc)
+}
+
+func serviceLink(svc *corev1.Service) (Link, error) {
 	svcType := svc.Spec.Type
 	if svcType != corev1.ServiceTypeLoadBalancer {
-		return "", fmt.Errorf("Service %s is of the type %s, not a LoadBalancer", name, svcType)
+		return Link{}, fmt.Errorf("Service %s is of the type %s, not a LoadBalancer", svc.Name, svcType)
 	}
 
 	id, ok := svc.Annotations[lbaasAnnotation]
 	if !ok {
-		return "", fmt.Errorf("annotation %s not found on service", lbaasAnnotation)
+		return Link{}, fmt.Errorf("annotation %s not found on service", lbaasAnnotation)
 	}
 
-	return fmt.Sprintf("networking/load_balancers/%s", id), nil
+	return newLink(ResourceLoadBalancer, id, fmt.Sprintf("Load Balancer %s (Service %s/%s)", id, svc.Namespace, svc.Name), fmt.Sprintf("networking/load_balancers/%s", id)), nil
 }
 
-func (cp *DOCloudPather) PersistentVolume(ctx context.Context, name string) (string, error) {
+func (cp *DOCloudPather) PersistentVolume(ctx context.Context, name string) (Link, error) {
 	pvObj, err := cp.clientset.CoreV1().PersistentVolumes().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return Link{}, err
+	}
+
+	return volumeLink(pvObj)
+}
+
+func volumeLink(pvObj *corev1.PersistentVolume) (Link, error) {
+	name := pvObj.Name
+
+	// volumes provisioned by the DO CSI driver carry the volume ID as their handle
+	if csi := pvObj.Spec.CSI; csi != nil && csi.Driver == csiDriverName {
+		if csi.VolumeHandle == "" {
+			return volumesFallback("PersistentVolume "+name, "it has an empty CSI volume handle"), nil
+		}
+
+		link := newLink(ResourceVolume, csi.VolumeHandle, fmt.Sprintf("Volume %s (PersistentVolume %s)", csi.VolumeHandle, name), fmt.Sprintf("volumes/%s", csi.VolumeHandle))
+		link.Region = volumeRegion(pvObj)
+		return link, nil
 	}
 
 	pvClass := pvObj.Spec.StorageClassName
-	if pvClass != storageClassName {
-		return "", fmt.Errorf("PersistentVolume %s is not a DigitalOcean Block Storage Volume. Storage class must be %s but got %s", name, storageClassName, pvClass)
+	if !isDOStorageClass(pvClass) {
+		return Link{}, fmt.Errorf("PersistentVolume %s is not a DigitalOcean Block Storage Volume. Storage class must be %s or one of its variants but got %s", name, storageClassName, pvClass)
 	}
 
-	fmt.Fprintf(cp.output, "PersistentVolume name: %s\n", pvObj.Name)
-	return "volumes", nil
+	return volumesFallback("PersistentVolume "+name, fmt.Sprintf("it is not provisioned by the %s CSI driver", csiDriverName)), nil
 }
 
-func (cp *DOCloudPather) PersistentVolumeClaim(ctx context.Context, namespace, name string) (string, error) {
+func (cp *DOCloudPather) PersistentVolumeClaim(ctx context.Context, namespace, name string) (Link, error) {
 	pvcObj, err := cp.clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		return "", err
+		return Link{}, err
+	}
+
+	// a bound claim is backed by a DigitalOcean Volume when its PersistentVolume is,
+	// whichever of the do-block-storage classes provisioned it
+	if pvcObj.Status.Phase == corev1.ClaimBound && pvcObj.Spec.VolumeName != "" {
+		return cp.PersistentVolume(ctx, pvcObj.Spec.VolumeName)
 	}
 
 	if pvcObj.Spec.StorageClassName == nil {
-		return "", fmt.Errorf("no StorageClassName found on PVC Spec")
+		return Link{}, fmt.Errorf("no StorageClassName found on PVC Spec")
+	}
+
+	pvClass := *pvcObj.Spec.StorageClassName
+	if !isDOStorageClass(pvClass) {
+		return Link{}, fmt.Errorf("PersistentVolumeClaim %s is not a DigitalOcean Block Storage Volume. Storage class must be %s or one of its variants but got %s", name, storageClassName, pvClass)
 	}
 
 	pvcPhase := pvcObj.Status.Phase
 	if pvcPhase != corev1.ClaimBound {
-		return "", fmt.Errorf("PersistentVolumeClaim %s is not bound to a PersistentVolume. Got phase %s", name, pvcPhase)
+		return Link{}, fmt.Errorf("PersistentVolumeClaim %s is not bound to a PersistentVolume. Got phase %s", name, pvcPhase)
 	}
 
-	pvClass := *pvcObj.Spec.StorageClassName
-	if pvClass != "do-block-storage" {
-		return "", fmt.Errorf("PersistentVolume %s is not a DigitalOcean Block Storage Volume. Storage class must be %s but got %s", name, "do-block-storage", pvClass)
+	return volumesFallback("PersistentVolumeClaim "+name, "the claim does not reference a PersistentVolume"), nil
+}
+
+// isDOStorageClass reports whether class is do-block-storage or one of the
+// variants DOKS installs next to it, such as do-block-storage-xfs
+func isDOStorageClass(class string) bool {
+	return class == storageClassName || strings.HasPrefix(class, storageClassName+"-")
+}
+
+// volumesFallback returns a link to the Volumes list page, noting why the
+// volume could not be deep-linked
+func volumesFallback(object, reason string) Link {
+	link := newLink(ResourceVolume, "", fmt.Sprintf("Volumes (%s)", object), "volumes")
+	link.Notes = []string{fmt.Sprintf("opening the Volumes list page because %s", reason)}
+	return link
+}
+
+// Pod returns the link of the Droplet running a pod, followed by the links of
+// the Volumes it claims
+func (cp *DOCloudPather) Pod(ctx context.Context, namespace, name string) ([]Link, error) {
+	pod, err := cp.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Fprintf(cp.output, "PersistentVolume name: %s\n", pvcObj.Spec.VolumeName)
-	return "volumes", nil
+	if pod.Spec.NodeName == "" {
+		return nil, fmt.Errorf("Pod %s has not been scheduled to a node yet. Got phase %s", name, pod.Status.Phase)
+	}
+
+	nodeLink, err := cp.Node(ctx, pod.Spec.NodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	links := []Link{nodeLink}
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim == nil {
+			continue
+		}
+
+		claimName := vol.PersistentVolumeClaim.ClaimName
+		link, err := cp.PersistentVolumeClaim(ctx, namespace, claimName)
+		if err != nil {
+			links[0].Notes = append(links[0].Notes, fmt.Sprintf("skipping volume %s (PersistentVolumeClaim %s): %s", vol.Name, claimName, err))
+			continue
+		}
+		links = appendLink(links, link)
+	}
+
+	return links, nil
+}
+
+// Workload returns the links of the Droplets and Volumes used by the pods
+// selected by a workload of the given kind.
+func (cp *DOCloudPather) Workload(ctx context.Context, kind, namespace, name string) ([]Link, error) {
+	selector, err := cp.workloadSelector(ctx, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if selector == nil {
+		return nil, fmt.Errorf("%s %s does not have a pod selector", kind, name)
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := cp.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	nodeNames := sets.NewString()
+	claimNames := sets.NewString()
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			nodeNames.Insert(pod.Spec.NodeName)
+		}
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim != nil {
+				claimNames.Insert(vol.PersistentVolumeClaim.ClaimName)
+			}
+		}
+	}
+
+	var links []Link
+	var notes []string
+	for _, nodeName := range nodeNames.List() {
+		link, err := cp.Node(ctx, nodeName)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("skipping Node %s: %s", nodeName, err))
+			continue
+		}
+		links = appendLink(links, link)
+	}
+
+	for _, claimName := range claimNames.List() {
+		link, err := cp.PersistentVolumeClaim(ctx, namespace, claimName)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("skipping PersistentVolumeClaim %s: %s", claimName, err))
+			continue
+		}
+		links = appendLink(links, link)
+	}
+
+	if len(links) == 0 {
+		return nil, fmt.Errorf("%s %s is not backed by any DigitalOcean resources. Found %d pods", kind, name, len(pods.Items))
+	}
+
+	links[0].Notes = append(links[0].Notes, notes...)
+	return links, nil
+}
+
+func (cp *DOCloudPather) workloadSelector(ctx context.Context, kind, namespace, name string) (*metav1.LabelSelector, error) {
+	switch kind {
+	case kindDeployment:
+		obj, err := cp.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.Spec.Selector, nil
+
+	case kindStatefulSet:
+		obj, err := cp.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.Spec.Selector, nil
+
+	case kindDaemonSet:
+		obj, err := cp.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.Spec.Selector, nil
+
+	case kindReplicaSet:
+		obj, err := cp.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.Spec.Selector, nil
+
+	case kindJob:
+		obj, err := cp.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.Spec.Selector, nil
+
+	default:
+		return nil, fmt.Errorf("unknown workload kind %s", kind)
+	}
+}
+
+// Ingress resolves an Ingress to the Load Balancer of the controller that
+// serves it. The controller's LoadBalancer Service is found by matching the
+// addresses in the Ingress status, falling back to the Ingress class.
+func (cp *DOCloudPather) Ingress(ctx context.Context, namespace, name string) (Link, error) {
+	ing, err := cp.getObject(ctx, networkingGroup, networkingVersions, "ingresses", namespace, name)
+	if err != nil {
+		return Link{}, err
+	}
+
+	lbServices, err := cp.loadBalancerServices(ctx)
+	if err != nil {
+		return Link{}, err
+	}
+
+	// match by address first, this works regardless of the controller in use
+	addresses := sets.NewString()
+	lbIngresses, _, _ := unstructured.NestedSlice(ing.Object, "status", "loadBalancer", "ingress")
+	for _, lbIngress := range lbIngresses {
+		if lbIngress, ok := lbIngress.(map[string]interface{}); ok {
+			ip, _, _ := unstructured.NestedString(lbIngress, "ip")
+			hostname, _, _ := unstructured.NestedString(lbIngress, "hostname")
+			addresses.Insert(ip, hostname)
+		}
+	}
+	addresses.Delete("")
+
+	if svc := serviceByAddress(lbServices, addresses); svc != nil {
+		return cp.Service(ctx, svc.Namespace, svc.Name)
+	}
+
+	className, _, _ := unstructured.NestedString(ing.Object, "spec", "ingressClassName")
+	if className == "" {
+		className = ing.GetAnnotations()[ingressClassAnnotation]
+	}
+	controllerName, err := cp.ingressControllerName(ctx, className)
+	if err != nil {
+		return Link{}, err
+	}
+	if controllerName == "" {
+		return Link{}, fmt.Errorf("Ingress %s has no load balancer addresses matching a LoadBalancer Service and no Ingress class", name)
+	}
+
+	var matches []corev1.Service
+	for _, svc := range lbServices {
+		if svc.Labels[appNameLabel] == controllerName {
+			matches = append(matches, svc)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Link{}, fmt.Errorf("no LoadBalancer Service labeled %s=%s found for the controller of Ingress %s", appNameLabel, controllerName, name)
+	case 1:
+		return cp.Service(ctx, matches[0].Namespace, matches[0].Name)
+	default:
+		return Link{}, fmt.Errorf("found %d LoadBalancer Services labeled %s=%s for the controller of Ingress %s", len(matches), appNameLabel, controllerName, name)
+	}
+}
+
+// ingressControllerName returns the name controller Services are labeled with,
+// e.g. ingress-nginx for the k8s.io/ingress-nginx controller. When the
+// IngressClass object does not exist, the class name itself is used.
+func (cp *DOCloudPather) ingressControllerName(ctx context.Context, class string) (string, error) {
+	if class == "" {
+		return "", nil
+	}
+
+	ingClass, err := cp.getObject(ctx, networkingGroup, networkingVersions, "ingressclasses", "", class)
+	if errors.IsNotFound(err) {
+		return class, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	controller, _, _ := unstructured.NestedString(ingClass.Object, "spec", "controller")
+	return controller[strings.LastIndex(controller, "/")+1:], nil
+}
+
+// getObject reads an object through the dynamic client, trying each version
+// of group in turn as clusters stop serving the older ones
+func (cp *DOCloudPather) getObject(ctx context.Context, group string, versions []string, resource, namespace, name string) (*unstructured.Unstructured, error) {
+	var err error
+	for _, version := range versions {
+		gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+
+		var obj *unstructured.Unstructured
+		obj, err = cp.dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			return obj, nil
+		}
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// loadBalancerServices lists the LoadBalancer Services in all namespaces
+func (cp *DOCloudPather) loadBalancerServices(ctx context.Context) ([]corev1.Service, error) {
+	svcList, err := cp.clientset.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var lbServices []corev1.Service
+	for _, svc := range svcList.Items {
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			lbServices = append(lbServices, svc)
+		}
+	}
+	return lbServices, nil
+}
+
+// serviceByAddress returns the first Service whose load balancer IP or
+// hostname is one of the given addresses
+func serviceByAddress(services []corev1.Service, addresses sets.String) *corev1.Service {
+	for i, svc := range services {
+		for _, lbIngress := range svc.Status.LoadBalancer.Ingress {
+			if (lbIngress.IP != "" && addresses.Has(lbIngress.IP)) || (lbIngress.Hostname != "" && addresses.Has(lbIngress.Hostname)) {
+				return &services[i]
+			}
+		}
+	}
+	return nil
 }
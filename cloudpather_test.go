@@ -14,24 +14,38 @@ limitations under the License.
 package kubectldoweb
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	restclient "k8s.io/client-go/rest"
 )
 
 func newFakeDOCloudPather() *DOCloudPather {
 	return &DOCloudPather{
-		clientset: fake.NewSimpleClientset(),
-		output:    &bytes.Buffer{},
+		clientset:     fake.NewSimpleClientset(),
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
 	}
 }
 
+// paths returns the URLs of links relative to the control panel
+func paths(links []Link) []string {
+	var paths []string
+	for _, link := range links {
+		paths = append(paths, link.path())
+	}
+	return paths
+}
+
 func TestDOCloudPather_Cluster(t *testing.T) {
 	cp := newFakeDOCloudPather()
 	id := "random-uuid"
@@ -76,8 +90,8 @@ func TestDOCloudPather_Cluster(t *testing.T) {
 				t.Errorf("DOCloudPather.Cluster() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
-				t.Errorf("DOCloudPather.Cluster() = %v, want %v", got, tt.want)
+			if got.path() != tt.want {
+				t.Errorf("DOCloudPather.Cluster() = %v, want %v", got.path(), tt.want)
 			}
 		})
 	}
@@ -143,8 +157,8 @@ func TestDOCloudPather_Node(t *testing.T) {
 				t.Errorf("DOCloudPather.Node() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
-				t.Errorf("DOCloudPather.Node() = %v, want %v", got, tt.want)
+			if got.path() != tt.want {
+				t.Errorf("DOCloudPather.Node() = %v, want %v", got.path(), tt.want)
 			}
 		})
 	}
@@ -228,8 +242,8 @@ func TestDOCloudPather_Service(t *testing.T) {
 				t.Errorf("DOCloudPather.Service() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
-				t.Errorf("DOCloudPather.Service() = %v, want %v", got, tt.want)
+			if got.path() != tt.want {
+				t.Errorf("DOCloudPather.Service() = %v, want %v", got.path(), tt.want)
 			}
 		})
 	}
@@ -238,26 +252,66 @@ func TestDOCloudPather_Service(t *testing.T) {
 func TestDOCloudPather_PersistentVolume(t *testing.T) {
 	ctx := context.TODO()
 	pvName := "pv-1"
+	volumeID := "random-id"
 
 	tests := []struct {
-		name       string
-		pvName     string
-		pv         *corev1.PersistentVolume
-		want       string
-		wantOutput string
-		wantErr    bool
+		name      string
+		pvName    string
+		pv        *corev1.PersistentVolume
+		want      string
+		wantNotes []string
+		wantErr   bool
 	}{
 		{
-			name:       "inexistent pv",
-			pvName:     pvName,
-			pv:         nil,
-			want:       "",
-			wantOutput: "",
-			wantErr:    true,
+			name:    "inexistent pv",
+			pvName:  pvName,
+			pv:      nil,
+			want:    "",
+			wantErr: true,
 		},
 		{
 			name:   "valid DO CSI pv",
 			pvName: pvName,
+			pv: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: pvName,
+				},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName: storageClassName,
+					PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{
+							Driver:       csiDriverName,
+							VolumeHandle: volumeID,
+						},
+					},
+				},
+			},
+			want:    fmt.Sprintf("volumes/%s", volumeID),
+			wantErr: false,
+		},
+		{
+			name:   "DO CSI pv with a custom storage class",
+			pvName: pvName,
+			pv: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: pvName,
+				},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName: "do-block-storage-retain",
+					PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{
+							Driver:       csiDriverName,
+							VolumeHandle: volumeID,
+						},
+					},
+				},
+			},
+			want:    fmt.Sprintf("volumes/%s", volumeID),
+			wantErr: false,
+		},
+		{
+			name:   "legacy DO pv without a CSI source",
+			pvName: pvName,
 			pv: &corev1.PersistentVolume{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: pvName,
@@ -266,9 +320,9 @@ func TestDOCloudPather_PersistentVolume(t *testing.T) {
 					StorageClassName: storageClassName,
 				},
 			},
-			want:       "volumes",
-			wantOutput: fmt.Sprintf("PersistentVolume name: %s\n", pvName),
-			wantErr:    false,
+			want:      "volumes",
+			wantNotes: []string{fmt.Sprintf("opening the Volumes list page because it is not provisioned by the %s CSI driver", csiDriverName)},
+			wantErr:   false,
 		},
 		{
 			name:   "non-DO CSI pv",
@@ -281,9 +335,8 @@ func TestDOCloudPather_PersistentVolume(t *testing.T) {
 					StorageClassName: "whomst",
 				},
 			},
-			want:       "",
-			wantOutput: "",
-			wantErr:    true,
+			want:    "",
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
@@ -298,13 +351,12 @@ func TestDOCloudPather_PersistentVolume(t *testing.T) {
 				t.Errorf("DOCloudPather.PersistentVolume() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
-				t.Errorf("DOCloudPather.PersistentVolume() = %v, want %v", got, tt.want)
+			if got.path() != tt.want {
+				t.Errorf("DOCloudPather.PersistentVolume() = %v, want %v", got.path(), tt.want)
 			}
 
-			gotOutput := cp.output.(*bytes.Buffer).String()
-			if gotOutput != tt.wantOutput {
-				t.Errorf("DOCloudPather.PersistentVolume() output = %v, want %v", gotOutput, tt.wantOutput)
+			if !reflect.DeepEqual(got.Notes, tt.wantNotes) {
+				t.Errorf("DOCloudPather.PersistentVolume() notes = %v, want %v", got.Notes, tt.wantNotes)
 			}
 		})
 	}
@@ -313,26 +365,28 @@ func TestDOCloudPather_PersistentVolume(t *testing.T) {
 func TestDOCloudPather_PersistentVolumeClaim(t *testing.T) {
 	ctx := context.TODO()
 	pvName := "pv-1"
+	volumeID := "random-id"
 	pvcName := "pvc-1"
 	namespace := "ns"
 	storageClassNameString := string(storageClassName)
+	xfsStorageClassName := storageClassName + "-xfs"
 	nonDOStorageClassName := "whomst"
 
 	tests := []struct {
-		name       string
-		pvcName    string
-		pvc        *corev1.PersistentVolumeClaim
-		want       string
-		wantOutput string
-		wantErr    bool
+		name      string
+		pvcName   string
+		pvc       *corev1.PersistentVolumeClaim
+		pv        *corev1.PersistentVolume
+		want      string
+		wantNotes []string
+		wantErr   bool
 	}{
 		{
-			name:       "inexistent pvc",
-			pvcName:    pvcName,
-			pvc:        nil,
-			want:       "",
-			wantOutput: "",
-			wantErr:    true,
+			name:    "inexistent pvc",
+			pvcName: pvcName,
+			pvc:     nil,
+			want:    "",
+			wantErr: true,
 		},
 		{
 			name:    "unbound/pending pvc",
@@ -348,9 +402,8 @@ func TestDOCloudPather_PersistentVolumeClaim(t *testing.T) {
 					Phase: corev1.ClaimPending,
 				},
 			},
-			want:       "",
-			wantOutput: "",
-			wantErr:    true,
+			want:    "",
+			wantErr: true,
 		},
 		{
 			name:    "pvc with nil storage class",
@@ -361,9 +414,8 @@ func TestDOCloudPather_PersistentVolumeClaim(t *testing.T) {
 				},
 				Spec: corev1.PersistentVolumeClaimSpec{},
 			},
-			want:       "",
-			wantOutput: "",
-			wantErr:    true,
+			want:    "",
+			wantErr: true,
 		},
 		{
 			name:    "valid DO CSI bound pvc",
@@ -380,9 +432,81 @@ func TestDOCloudPather_PersistentVolumeClaim(t *testing.T) {
 					Phase: corev1.ClaimBound,
 				},
 			},
-			want:       "volumes",
-			wantOutput: fmt.Sprintf("PersistentVolume name: %s\n", pvName),
-			wantErr:    false,
+			pv: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: pvName,
+				},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName: storageClassName,
+					PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{
+							Driver:       csiDriverName,
+							VolumeHandle: volumeID,
+						},
+					},
+				},
+			},
+			want:    fmt.Sprintf("volumes/%s", volumeID),
+			wantErr: false,
+		},
+		{
+			name:    "legacy DO bound pvc",
+			pvcName: pvcName,
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: pvcName,
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					VolumeName:       pvName,
+					StorageClassName: &storageClassNameString,
+				},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Phase: corev1.ClaimBound,
+				},
+			},
+			pv: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: pvName,
+				},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName: storageClassName,
+				},
+			},
+			want:      "volumes",
+			wantNotes: []string{fmt.Sprintf("opening the Volumes list page because it is not provisioned by the %s CSI driver", csiDriverName)},
+			wantErr:   false,
+		},
+		{
+			name:    "DO CSI bound pvc of an xfs storage class",
+			pvcName: pvcName,
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: pvcName,
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					VolumeName:       pvName,
+					StorageClassName: &xfsStorageClassName,
+				},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Phase: corev1.ClaimBound,
+				},
+			},
+			pv: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: pvName,
+				},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName: xfsStorageClassName,
+					PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{
+							Driver:       csiDriverName,
+							VolumeHandle: volumeID,
+						},
+					},
+				},
+			},
+			want:    fmt.Sprintf("volumes/%s", volumeID),
+			wantErr: false,
 		},
 		{
 			name:    "non-DO CSI bound pvc",
@@ -399,9 +523,8 @@ func TestDOCloudPather_PersistentVolumeClaim(t *testing.T) {
 					Phase: corev1.ClaimBound,
 				},
 			},
-			want:       "",
-			wantOutput: "",
-			wantErr:    true,
+			want:    "",
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
@@ -410,19 +533,553 @@ func TestDOCloudPather_PersistentVolumeClaim(t *testing.T) {
 			if tt.pvc != nil {
 				cp.clientset.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, tt.pvc, metav1.CreateOptions{})
 			}
+			if tt.pv != nil {
+				cp.clientset.CoreV1().PersistentVolumes().Create(ctx, tt.pv, metav1.CreateOptions{})
+			}
 
 			got, err := cp.PersistentVolumeClaim(ctx, namespace, tt.pvcName)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DOCloudPather.PersistentVolumeClaim() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
-				t.Errorf("DOCloudPather.PersistentVolumeClaim() = %v, want %v", got, tt.want)
+			if got.path() != tt.want {
+				t.Errorf("DOCloudPather.PersistentVolumeClaim() = %v, want %v", got.path(), tt.want)
+			}
+
+			if !reflect.DeepEqual(got.Notes, tt.wantNotes) {
+				t.Errorf("DOCloudPather.PersistentVolumeClaim() notes = %v, want %v", got.Notes, tt.wantNotes)
+			}
+		})
+	}
+}
+
+func TestDOCloudPather_Pod(t *testing.T) {
+	ctx := context.TODO()
+	id := "random-id"
+	volumeID := "random-volume-id"
+	namespace := "ns"
+	podName := "pod-1"
+	nodeName := "node-1"
+	pvcName := "pvc-1"
+	pvName := "pv-1"
+	storageClassNameString := string(storageClassName)
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeName,
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID: fmt.Sprintf("%s%s", nodeIDPrefix, id),
+		},
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: pvcName,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			VolumeName:       pvName,
+			StorageClassName: &storageClassNameString,
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase: corev1.ClaimBound,
+		},
+	}
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: pvName,
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:       csiDriverName,
+					VolumeHandle: volumeID,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		podName string
+		pod     *corev1.Pod
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "inexistent pod",
+			podName: podName,
+			pod:     nil,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "pending pod",
+			podName: podName,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: podName,
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "scheduled pod",
+			podName: podName,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: podName,
+				},
+				Spec: corev1.PodSpec{
+					NodeName: nodeName,
+				},
+			},
+			want:    []string{fmt.Sprintf("droplets/%s", id)},
+			wantErr: false,
+		},
+		{
+			name:    "scheduled pod with volumes",
+			podName: podName,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: podName,
+				},
+				Spec: corev1.PodSpec{
+					NodeName: nodeName,
+					Volumes: []corev1.Volume{
+						{
+							Name: "config",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{},
+							},
+						},
+						{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: pvcName,
+								},
+							},
+						},
+					},
+				},
+			},
+			want:    []string{fmt.Sprintf("droplets/%s", id), fmt.Sprintf("volumes/%s", volumeID)},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := newFakeDOCloudPather()
+			cp.clientset.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{})
+			cp.clientset.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metav1.CreateOptions{})
+			cp.clientset.CoreV1().PersistentVolumes().Create(ctx, pv, metav1.CreateOptions{})
+			if tt.pod != nil {
+				cp.clientset.CoreV1().Pods(namespace).Create(ctx, tt.pod, metav1.CreateOptions{})
+			}
+
+			got, err := cp.Pod(ctx, namespace, tt.podName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DOCloudPather.Pod() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := paths(got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DOCloudPather.Pod() = %v, want %v", got, tt.want)
 			}
+		})
+	}
+}
+
+func TestDOCloudPather_Workload(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+	stsName := "sts-1"
+	storageClassNameString := string(storageClassName)
+	labels := map[string]string{"app": "db"}
 
-			gotOutput := cp.output.(*bytes.Buffer).String()
-			if gotOutput != tt.wantOutput {
-				t.Errorf("DOCloudPather.PersistentVolumeClaim() output = %v, want %v", gotOutput, tt.wantOutput)
+	newPod := func(name, nodeName, claimName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    labels,
+			},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Volumes: []corev1.Volume{
+					{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: claimName,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Spec:       corev1.NodeSpec{ProviderID: fmt.Sprintf("%s%s", nodeIDPrefix, "droplet-1")},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-2"},
+			Spec:       corev1.NodeSpec{ProviderID: fmt.Sprintf("%s%s", nodeIDPrefix, "droplet-2")},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-sts-1-0", Namespace: namespace},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				VolumeName:       "pv-0",
+				StorageClassName: &storageClassNameString,
+			},
+			Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-0"},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: csiDriverName, VolumeHandle: "volume-0"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		kind      string
+		objects   []runtime.Object
+		want      []string
+		wantNotes []string
+		wantErr   bool
+	}{
+		{
+			name:    "inexistent workload",
+			kind:    kindStatefulSet,
+			objects: nil,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "workload without pods",
+			kind: kindStatefulSet,
+			objects: []runtime.Object{
+				&appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: stsName, Namespace: namespace},
+					Spec:       appsv1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "statefulset with volume claims",
+			kind: kindStatefulSet,
+			objects: []runtime.Object{
+				&appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: stsName, Namespace: namespace},
+					Spec:       appsv1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+				},
+				newPod("sts-1-0", "node-1", "data-sts-1-0"),
+				newPod("sts-1-1", "node-2", "data-sts-1-1"),
+				newPod("sts-1-2", "node-2", "data-sts-1-0"),
+			},
+			want:      []string{"droplets/droplet-1", "droplets/droplet-2", "volumes/volume-0"},
+			wantNotes: []string{"skipping PersistentVolumeClaim data-sts-1-1: persistentvolumeclaims \"data-sts-1-1\" not found"},
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := newFakeDOCloudPather()
+			for _, obj := range append(objects, tt.objects...) {
+				cp.clientset.(*fake.Clientset).Tracker().Add(obj)
+			}
+
+			got, err := cp.Workload(ctx, tt.kind, namespace, stsName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DOCloudPather.Workload() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(paths(got), tt.want) {
+				t.Errorf("DOCloudPather.Workload() = %v, want %v", paths(got), tt.want)
+			}
+
+			var gotNotes []string
+			if len(got) > 0 {
+				gotNotes = got[0].Notes
+			}
+			if !reflect.DeepEqual(gotNotes, tt.wantNotes) {
+				t.Errorf("DOCloudPather.Workload() notes = %v, want %v", gotNotes, tt.wantNotes)
+			}
+		})
+	}
+}
+
+// newIngressObject returns a networking.k8s.io object of version, as read
+// through the dynamic client
+func newIngressObject(version, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	obj.SetAPIVersion(networkingGroup + "/" + version)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestDOCloudPather_Ingress(t *testing.T) {
+	ctx := context.TODO()
+	id := "random-id"
+	namespace := "ns"
+	ingName := "ing-1"
+	className := "nginx"
+	lbIP := "203.0.113.10"
+
+	controllerService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-nginx-controller",
+			Namespace: "ingress-nginx",
+			Labels: map[string]string{
+				appNameLabel: "ingress-nginx",
+			},
+			Annotations: map[string]string{
+				lbaasAnnotation: id,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: lbIP}},
+			},
+		},
+	}
+	ingressClass := newIngressObject("v1", "IngressClass", "", className, map[string]interface{}{
+		"spec": map[string]interface{}{"controller": "k8s.io/ingress-nginx"},
+	})
+	lbStatus := map[string]interface{}{
+		"loadBalancer": map[string]interface{}{
+			"ingress": []interface{}{map[string]interface{}{"ip": lbIP}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		ingName string
+		ingress *unstructured.Unstructured
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "inexistent ingress",
+			ingName: ingName,
+			ingress: nil,
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "ingress matched by address",
+			ingName: ingName,
+			ingress: newIngressObject("v1", "Ingress", namespace, ingName, map[string]interface{}{"status": lbStatus}),
+			want:    fmt.Sprintf("networking/load_balancers/%s", id),
+			wantErr: false,
+		},
+		{
+			name:    "v1beta1 ingress matched by address",
+			ingName: ingName,
+			ingress: newIngressObject("v1beta1", "Ingress", namespace, ingName, map[string]interface{}{"status": lbStatus}),
+			want:    fmt.Sprintf("networking/load_balancers/%s", id),
+			wantErr: false,
+		},
+		{
+			name:    "ingress matched by class",
+			ingName: ingName,
+			ingress: newIngressObject("v1", "Ingress", namespace, ingName, map[string]interface{}{
+				"spec": map[string]interface{}{"ingressClassName": className},
+			}),
+			want:    fmt.Sprintf("networking/load_balancers/%s", id),
+			wantErr: false,
+		},
+		{
+			name:    "ingress matched by class annotation",
+			ingName: ingName,
+			ingress: func() *unstructured.Unstructured {
+				ing := newIngressObject("v1", "Ingress", namespace, ingName, map[string]interface{}{})
+				ing.SetAnnotations(map[string]string{ingressClassAnnotation: "ingress-nginx"})
+				return ing
+			}(),
+			want:    fmt.Sprintf("networking/load_balancers/%s", id),
+			wantErr: false,
+		},
+		{
+			name:    "ingress without address or class",
+			ingName: ingName,
+			ingress: newIngressObject("v1", "Ingress", namespace, ingName, map[string]interface{}{}),
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := newFakeDOCloudPather()
+			cp.clientset.CoreV1().Services(controllerService.Namespace).Create(ctx, controllerService, metav1.CreateOptions{})
+			classes := schema.GroupVersionResource{Group: networkingGroup, Version: "v1", Resource: "ingressclasses"}
+			cp.dynamicClient.Resource(classes).Create(ctx, ingressClass, metav1.CreateOptions{})
+			if tt.ingress != nil {
+				ingresses := tt.ingress.GroupVersionKind().GroupVersion().WithResource("ingresses")
+				cp.dynamicClient.Resource(ingresses).Namespace(namespace).Create(ctx, tt.ingress, metav1.CreateOptions{})
+			}
+
+			got, err := cp.Ingress(ctx, namespace, tt.ingName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DOCloudPather.Ingress() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.path() != tt.want {
+				t.Errorf("DOCloudPather.Ingress() = %v, want %v", got.path(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDOCloudPather_NodePool(t *testing.T) {
+	ctx := context.TODO()
+	clusterID := "cluster-id"
+	clusterPath := fmt.Sprintf("kubernetes/clusters/%s", clusterID)
+
+	newNode := func(name, poolID, poolName string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					nodePoolIDLabel:   poolID,
+					nodePoolNameLabel: poolName,
+				},
+			},
+		}
+	}
+	nodes := []*corev1.Node{
+		newNode("web-1", "pool-id-2", "web"),
+		newNode("web-2", "pool-id-2", "web"),
+		newNode("db-1", "pool-id-1", "db"),
+		{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"}},
+	}
+
+	tests := []struct {
+		name     string
+		poolName string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "pool by name",
+			poolName: "web",
+			want:     []string{fmt.Sprintf("%s/nodepools/pool-id-2", clusterPath)},
+			wantErr:  false,
+		},
+		{
+			name:     "pool by id",
+			poolName: "pool-id-1",
+			want:     []string{fmt.Sprintf("%s/nodepools/pool-id-1", clusterPath)},
+			wantErr:  false,
+		},
+		{
+			name:     "inexistent pool",
+			poolName: "whomst",
+			want:     nil,
+			wantErr:  true,
+		},
+		{
+			name:     "list pools",
+			poolName: "",
+			want:     []string{fmt.Sprintf("%s/nodepools/pool-id-1", clusterPath), fmt.Sprintf("%s/nodepools/pool-id-2", clusterPath)},
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := newFakeDOCloudPather()
+			cp.clientConfig = &restclient.Config{
+				Host: fmt.Sprintf("https://%s%s", clusterID, hostnameSuffix),
+			}
+			for _, node := range nodes {
+				cp.clientset.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{})
+			}
+
+			got, err := cp.NodePool(ctx, tt.poolName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DOCloudPather.NodePool() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := paths(got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DOCloudPather.NodePool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDOCloudPather_PoolOfNode(t *testing.T) {
+	ctx := context.TODO()
+	clusterID := "cluster-id"
+	nodeName := "node-1"
+
+	tests := []struct {
+		name    string
+		node    *corev1.Node
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "inexistent node",
+			node:    nil,
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "DOKS node",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   nodeName,
+					Labels: map[string]string{nodePoolIDLabel: "pool-id"},
+				},
+			},
+			want:    fmt.Sprintf("kubernetes/clusters/%s/nodepools/pool-id", clusterID),
+			wantErr: false,
+		},
+		{
+			name: "node without pool label",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: nodeName,
+				},
+			},
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := newFakeDOCloudPather()
+			cp.clientConfig = &restclient.Config{
+				Host: fmt.Sprintf("https://%s%s", clusterID, hostnameSuffix),
+			}
+			if tt.node != nil {
+				cp.clientset.CoreV1().Nodes().Create(ctx, tt.node, metav1.CreateOptions{})
+			}
+
+			got, err := cp.PoolOfNode(ctx, nodeName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DOCloudPather.PoolOfNode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.path() != tt.want {
+				t.Errorf("DOCloudPather.PoolOfNode() = %v, want %v", got.path(), tt.want)
 			}
 		})
 	}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/do-community/kubectldoweb"
+	"github.com/urfave/cli/v2"
+)
+
+func newDiffCmd(diff kubectldoweb.DiffRunner) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if c.Args().Len() != 2 {
+			return fmt.Errorf("diff takes two inventory files or kube contexts")
+		}
+
+		if err := checkContextFlags(c, "diff"); err != nil {
+			return err
+		}
+		kubeConfig, err := kubeConfigFrom(c)
+		if err != nil {
+			return err
+		}
+
+		changes, err := diff(c.Context, os.Stderr, kubeConfig, kubectldoweb.RunOptions{ContextOverrides: configOverridesFrom(c)}, c.Args().Get(0), c.Args().Get(1))
+		if err != nil {
+			return err
+		}
+
+		if len(changes) == 0 {
+			fmt.Fprintln(os.Stderr, "No differences found.")
+			return nil
+		}
+		if err := printChanges(os.Stdout, changes); err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if change.Change == kubectldoweb.InventoryRepointed && change.Type == string(kubectldoweb.ResourceLoadBalancer) {
+				fmt.Fprintf(os.Stderr, "%s (namespace %s) was given a new Load Balancer, its IP address has probably changed\n", ownerName(change.Owner), change.Owner.Namespace)
+			}
+		}
+
+		// like diff, differences are reported with a non-zero exit status
+		return fmt.Errorf("%d DigitalOcean resources changed", len(changes))
+	}
+}
+
+// printChanges prints a line per change with the IDs of the resource before and after it
+func printChanges(w io.Writer, changes []kubectldoweb.InventoryChange) error {
+	tw := tabwriter.NewWriter(w, 6, 4, 3, ' ', 0)
+	fmt.Fprintln(tw, "CHANGE\tTYPE\tNAMESPACE\tOWNER\tFROM\tTO")
+	for _, change := range changes {
+		namespace := ""
+		if change.Owner != nil {
+			namespace = change.Owner.Namespace
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", change.Change, change.Type, namespace, ownerName(change.Owner), itemID(change.From), itemID(change.To))
+	}
+	return tw.Flush()
+}
+
+func ownerName(owner *kubectldoweb.InventoryOwner) string {
+	if owner == nil {
+		return "<none>"
+	}
+	return strings.ToLower(owner.Kind) + "/" + owner.Name
+}
+
+func itemID(item *kubectldoweb.InventoryItem) string {
+	switch {
+	case item == nil:
+		return ""
+	case item.ID == "":
+		return "<unknown>"
+	}
+	return item.ID
+}
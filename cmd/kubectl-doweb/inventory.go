@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/do-community/kubectldoweb"
+	"github.com/urfave/cli/v2"
+)
+
+type inventoryPrinter func(w io.Writer, inventory *kubectldoweb.Inventory) error
+
+// inventoryFormats are the values accepted by inventory --format, mapped to their printers
+var inventoryFormats = map[string]inventoryPrinter{
+	"csv":      printInventoryCSV,
+	"json":     printInventoryJSON,
+	"markdown": printInventoryMarkdown,
+}
+
+var inventoryColumns = []string{"type", "id", "region", "url", "owner_kind", "namespace", "name", "workloads", "labels"}
+
+func newInventoryCmd(fetch kubectldoweb.InventoryRunner) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if c.Args().Len() > 0 {
+			return fmt.Errorf("inventory does not take arguments")
+		}
+
+		format := c.String("format")
+		printInventory, ok := inventoryFormats[format]
+		if !ok {
+			return fmt.Errorf("unknown inventory format %s, expected one of: csv, json, markdown", format)
+		}
+
+		kubeConfig, err := kubeConfigFrom(c)
+		if err != nil {
+			return err
+		}
+
+		inventory, err := fetch(c.Context, os.Stderr, kubeConfig, kubectldoweb.RunOptions{
+			Filenames:   c.StringSlice("filename"),
+			ClusterID:   c.String("cluster-id"),
+			AllContexts: c.Bool("all-contexts"),
+		})
+		if err != nil {
+			return err
+		}
+		return printInventory(os.Stdout, inventory)
+	}
+}
+
+func printInventoryJSON(w io.Writer, inventory *kubectldoweb.Inventory) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(inventory)
+}
+
+func printInventoryCSV(w io.Writer, inventory *kubectldoweb.Inventory) error {
+	cw := csv.NewWriter(w)
+	cw.Write(inventoryColumns)
+	for _, item := range inventory.Items {
+		cw.Write(inventoryRow(item, ";"))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// printInventoryMarkdown prints a table, linking the IDs to the control panel
+func printInventoryMarkdown(w io.Writer, inventory *kubectldoweb.Inventory) error {
+	if inventory.ClusterID != "" {
+		fmt.Fprintf(w, "# DigitalOcean resources of cluster %s\n\n", inventory.ClusterID)
+	}
+
+	fmt.Fprintln(w, "| Type | ID | Region | Owner | Namespace | Workloads | Labels |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- | --- | --- |")
+	for _, item := range inventory.Items {
+		row := inventoryRow(item, ", ")
+		id := row[1]
+		if id == "" {
+			id = "(unknown)"
+		}
+		owner := ""
+		if row[4] != "" {
+			owner = strings.ToLower(row[4]) + "/" + row[6]
+		}
+
+		cells := []string{row[0], fmt.Sprintf("[%s](%s)", id, row[3]), row[2], owner, row[5], row[7], row[8]}
+		for i, cell := range cells {
+			cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return nil
+}
+
+// inventoryRow returns the cells of item in the order of inventoryColumns,
+// joining workloads and labels with sep
+func inventoryRow(item kubectldoweb.InventoryItem, sep string) []string {
+	row := []string{item.Type, item.ID, item.Region, item.URL, "", "", "", "", ""}
+	if owner := item.Owner; owner != nil {
+		keys := make([]string, 0, len(owner.Labels))
+		for key := range owner.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		labels := make([]string, 0, len(keys))
+		for _, key := range keys {
+			labels = append(labels, key+"="+owner.Labels[key])
+		}
+
+		row[4], row[5], row[6] = owner.Kind, owner.Namespace, owner.Name
+		row[7] = strings.Join(owner.Workloads, sep)
+		row[8] = strings.Join(labels, sep)
+	}
+	return row
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/do-community/kubectldoweb"
+)
+
+func Test_inventoryRow(t *testing.T) {
+	tests := []struct {
+		name string
+		item kubectldoweb.InventoryItem
+		sep  string
+		want []string
+	}{
+		{
+			name: "cluster",
+			item: kubectldoweb.InventoryItem{Type: "kubernetes_cluster", ID: "cluster-id", Region: "nyc1", URL: "url"},
+			sep:  ";",
+			want: []string{"kubernetes_cluster", "cluster-id", "nyc1", "url", "", "", "", "", ""},
+		},
+		{
+			name: "owner with workloads and labels",
+			item: kubectldoweb.InventoryItem{
+				Type: "load_balancer",
+				ID:   "lb-id",
+				URL:  "url",
+				Owner: &kubectldoweb.InventoryOwner{
+					Kind:      "Service",
+					Namespace: "default",
+					Name:      "web",
+					Workloads: []string{"deployment/web", "statefulset/cache"},
+					Labels:    map[string]string{"team": "payments", "app": "web"},
+				},
+			},
+			sep:  ";",
+			want: []string{"load_balancer", "lb-id", "", "url", "Service", "default", "web", "deployment/web;statefulset/cache", "app=web;team=payments"},
+		},
+		{
+			name: "cluster-scoped owner",
+			item: kubectldoweb.InventoryItem{
+				Type:  "volume",
+				URL:   "url",
+				Owner: &kubectldoweb.InventoryOwner{Kind: "PersistentVolume", Name: "pv-1"},
+			},
+			sep:  ", ",
+			want: []string{"volume", "", "", "url", "PersistentVolume", "", "pv-1", "", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inventoryRow(tt.item, tt.sep); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("inventoryRow() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_printInventoryMarkdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		inventory *kubectldoweb.Inventory
+		want      string
+	}{
+		{
+			name: "cluster and owner",
+			inventory: &kubectldoweb.Inventory{
+				ClusterID: "cluster-id",
+				Items: []kubectldoweb.InventoryItem{
+					{Type: "kubernetes_cluster", ID: "cluster-id", URL: "https://cloud.digitalocean.com/kubernetes/clusters/cluster-id"},
+					{
+						Type:  "load_balancer",
+						ID:    "lb-id",
+						URL:   "https://cloud.digitalocean.com/networking/load_balancers/lb-id",
+						Owner: &kubectldoweb.InventoryOwner{Kind: "Service", Namespace: "default", Name: "web", Labels: map[string]string{"app": "web"}},
+					},
+				},
+			},
+			want: "# DigitalOcean resources of cluster cluster-id\n\n" +
+				"| Type | ID | Region | Owner | Namespace | Workloads | Labels |\n" +
+				"| --- | --- | --- | --- | --- | --- | --- |\n" +
+				"| kubernetes_cluster | [cluster-id](https://cloud.digitalocean.com/kubernetes/clusters/cluster-id) |  |  |  |  |  |\n" +
+				"| load_balancer | [lb-id](https://cloud.digitalocean.com/networking/load_balancers/lb-id) |  | service/web | default |  | app=web |\n",
+		},
+		{
+			name: "unknown ID and pipes",
+			inventory: &kubectldoweb.Inventory{
+				Items: []kubectldoweb.InventoryItem{
+					{
+						Type:  "volume",
+						URL:   "https://cloud.digitalocean.com/volumes",
+						Owner: &kubectldoweb.InventoryOwner{Kind: "PersistentVolume", Name: "pv-1", Labels: map[string]string{"a|b": "c|d"}},
+					},
+				},
+			},
+			want: "| Type | ID | Region | Owner | Namespace | Workloads | Labels |\n" +
+				"| --- | --- | --- | --- | --- | --- | --- |\n" +
+				"| volume | [(unknown)](https://cloud.digitalocean.com/volumes) |  | persistentvolume/pv-1 |  |  | a\\|b=c\\|d |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := printInventoryMarkdown(&out, tt.inventory); err != nil {
+				t.Fatalf("printInventoryMarkdown() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("printInventoryMarkdown() = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// kubeConfigFlags are the kubectl flags selecting and overriding the kubeconfig
+// cluster, user and context, named like kubectl's
+func kubeConfigFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        clientcmd.RecommendedConfigPathFlag,
+			Usage:       "path to the kubeconfig file",
+			DefaultText: "$KUBECONFIG or $HOME/.kube/config",
+		},
+		&cli.StringFlag{
+			Name:  clientcmd.FlagContext,
+			Usage: "the name of the kubeconfig context to use",
+		},
+		&cli.StringFlag{
+			Name:  clientcmd.FlagClusterName,
+			Usage: "the name of the kubeconfig cluster to use",
+		},
+		&cli.StringFlag{
+			Name:  clientcmd.FlagAuthInfoName,
+			Usage: "the name of the kubeconfig user to use",
+		},
+		&cli.StringFlag{
+			Name:  clientcmd.FlagImpersonate,
+			Usage: "username to impersonate for the operation",
+		},
+		&cli.StringSliceFlag{
+			Name:  clientcmd.FlagImpersonateGroup,
+			Usage: "group to impersonate for the operation, can be repeated",
+		},
+		&cli.StringFlag{
+			Name:  clientcmd.FlagBearerToken,
+			Usage: "bearer token for authentication to the API server",
+		},
+		&cli.StringFlag{
+			Name:    clientcmd.FlagAPIServer,
+			Aliases: []string{"s"},
+			Usage:   "the address and port of the Kubernetes API server",
+		},
+		&cli.StringFlag{
+			Name:  clientcmd.FlagCAFile,
+			Usage: "path to a cert file for the certificate authority",
+		},
+		&cli.StringFlag{
+			Name:  clientcmd.FlagCertFile,
+			Usage: "path to a client certificate file for TLS",
+		},
+		&cli.StringFlag{
+			Name:  clientcmd.FlagKeyFile,
+			Usage: "path to a client key file for TLS",
+		},
+		&cli.BoolFlag{
+			Name:  clientcmd.FlagInsecure,
+			Usage: "do not check the server's certificate for validity, making HTTPS connections insecure",
+		},
+		&cli.StringFlag{
+			Name:  clientcmd.FlagTimeout,
+			Usage: "the length of time to wait before giving up on a single server request, e.g. 1s, 2m or 3h. 0 means no timeout",
+			Value: "0",
+		},
+	}
+}
+
+// kubeConfigFrom loads the kubeconfig the way kubectl does: --kubeconfig, or the
+// files of $KUBECONFIG merged, or $HOME/.kube/config, with the flags overriding
+// the selected context
+func kubeConfigFrom(c *cli.Context) (clientcmd.ClientConfig, error) {
+	if c.Bool("all-contexts") {
+		if err := checkContextFlags(c, "--all-contexts"); err != nil {
+			return nil, err
+		}
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = c.String(clientcmd.RecommendedConfigPathFlag)
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverridesFrom(c)), nil
+}
+
+// contextFlags pick or authenticate against the cluster of a single context,
+// so they make no sense across contexts and must not be sent to every cluster
+var contextFlags = []string{
+	clientcmd.FlagContext,
+	clientcmd.FlagClusterName,
+	clientcmd.FlagAuthInfoName,
+	clientcmd.FlagBearerToken,
+	clientcmd.FlagAPIServer,
+	clientcmd.FlagCAFile,
+	clientcmd.FlagCertFile,
+	clientcmd.FlagKeyFile,
+}
+
+// checkContextFlags fails when one of contextFlags is set for a mode that
+// covers several contexts, such as --all-contexts
+func checkContextFlags(c *cli.Context, mode string) error {
+	for _, name := range contextFlags {
+		if c.String(name) != "" {
+			return fmt.Errorf("--%s cannot be combined with %s", name, mode)
+		}
+	}
+	if c.Bool(clientcmd.FlagInsecure) {
+		return fmt.Errorf("--%s cannot be combined with %s", clientcmd.FlagInsecure, mode)
+	}
+	return nil
+}
+
+// configOverridesFrom returns the kubeconfig overrides of the flags. Only the
+// request timeout and impersonation apply to the contexts searched with
+// --all-contexts or compared by diff.
+func configOverridesFrom(c *cli.Context) *clientcmd.ConfigOverrides {
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: c.String(clientcmd.FlagContext),
+		Timeout:        c.String(clientcmd.FlagTimeout),
+	}
+	overrides.Context.Cluster = c.String(clientcmd.FlagClusterName)
+	overrides.Context.AuthInfo = c.String(clientcmd.FlagAuthInfoName)
+	overrides.AuthInfo.Impersonate = c.String(clientcmd.FlagImpersonate)
+	overrides.AuthInfo.ImpersonateGroups = c.StringSlice(clientcmd.FlagImpersonateGroup)
+	overrides.AuthInfo.Token = c.String(clientcmd.FlagBearerToken)
+	overrides.AuthInfo.ClientCertificate = c.String(clientcmd.FlagCertFile)
+	overrides.AuthInfo.ClientKey = c.String(clientcmd.FlagKeyFile)
+	overrides.ClusterInfo.Server = c.String(clientcmd.FlagAPIServer)
+	overrides.ClusterInfo.CertificateAuthority = c.String(clientcmd.FlagCAFile)
+	overrides.ClusterInfo.InsecureSkipTLSVerify = c.Bool(clientcmd.FlagInsecure)
+	return overrides
+}
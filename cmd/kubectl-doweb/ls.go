@@ -0,0 +1,175 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/do-community/kubectldoweb"
+	"github.com/urfave/cli/v2"
+)
+
+// lsColumns are the columns of the ls table, the last three being shown with -o wide only
+var lsColumns = []string{"namespace", "name", "type", "id", "url", "region", "size", "pool"}
+
+// lsRow is an object of the ls table and the DigitalOcean resource it resolves to
+type lsRow struct {
+	namespace, name string
+	link            kubectldoweb.Link
+	size, pool      string
+}
+
+func (r lsRow) column(name string) string {
+	switch name {
+	case "namespace":
+		return r.namespace
+	case "name":
+		return r.name
+	case "type":
+		return string(r.link.Kind)
+	case "id":
+		return r.link.ID
+	case "url":
+		return r.link.URL
+	case "region":
+		return r.link.Region
+	case "size":
+		return r.size
+	case "pool":
+		return r.pool
+	}
+	return ""
+}
+
+func newLsCmd(fetch kubectldoweb.OverviewRunner) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if c.Args().Len() > 0 {
+			return fmt.Errorf("ls does not take arguments")
+		}
+
+		wide := false
+		switch output := c.String("output"); output {
+		case "":
+		case "wide":
+			wide = true
+		default:
+			return fmt.Errorf("ls only supports the wide output format, got %s", output)
+		}
+
+		sortBy := strings.ToLower(c.String("sort-by"))
+		if sortBy != "" && !contains(lsColumns, sortBy) {
+			return fmt.Errorf("cannot sort by %s, expected one of: %s", sortBy, strings.Join(lsColumns, ", "))
+		}
+
+		kubeConfig, err := kubeConfigFrom(c)
+		if err != nil {
+			return err
+		}
+		allNamespaces := c.Bool("all-namespaces")
+		namespace := c.String("namespace")
+		if namespace == "" && !allNamespaces {
+			namespace, _, _ = kubeConfig.Namespace()
+			if namespace == "" {
+				namespace = "default"
+			}
+		}
+
+		overview, err := fetch(c.Context, os.Stderr, kubeConfig, kubectldoweb.RunOptions{
+			Filenames:   c.StringSlice("filename"),
+			ClusterID:   c.String("cluster-id"),
+			AllContexts: c.Bool("all-contexts"),
+		})
+		if err != nil {
+			return err
+		}
+
+		var rows []lsRow
+		for _, entries := range [][]kubectldoweb.OverviewEntry{overview.Nodes, overview.LoadBalancers, overview.Volumes, overview.Claims} {
+			for _, entry := range entries {
+				// cluster-scoped objects are always listed, like kubectl get does
+				if entry.Namespace != "" && !allNamespaces && entry.Namespace != namespace {
+					continue
+				}
+				name := strings.ToLower(entry.Kind) + "/" + entry.Name
+				if entry.Err != nil {
+					fmt.Fprintf(os.Stderr, "skipping %s (namespace %s): %s\n", name, entry.Namespace, entry.Err)
+					continue
+				}
+
+				rows = append(rows, lsRow{
+					namespace: entry.Namespace,
+					name:      name,
+					link:      entry.Links[0],
+					size:      entry.Size,
+					pool:      entry.Pool,
+				})
+			}
+		}
+
+		if len(rows) == 0 {
+			if allNamespaces {
+				fmt.Fprintln(os.Stderr, "No resources found.")
+			} else {
+				fmt.Fprintf(os.Stderr, "No resources found in %s namespace.\n", namespace)
+			}
+			return nil
+		}
+
+		if sortBy != "" {
+			sort.SliceStable(rows, func(i, j int) bool { return rows[i].column(sortBy) < rows[j].column(sortBy) })
+		}
+		return printLs(os.Stdout, rows, allNamespaces, wide)
+	}
+}
+
+// printLs prints rows like kubectl get does, with a NAMESPACE column when
+// every namespace is listed and the region, size and pool with wide
+func printLs(w io.Writer, rows []lsRow, allNamespaces, wide bool) error {
+	columns := lsColumns[1:5]
+	if allNamespaces {
+		columns = lsColumns[:5]
+	}
+	if wide {
+		columns = append(columns[:len(columns):len(columns)], lsColumns[5:]...)
+	}
+
+	tw := tabwriter.NewWriter(w, 6, 4, 3, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range rows {
+		cells := make([]string, 0, len(columns))
+		for _, column := range columns {
+			cell := row.column(column)
+			if cell == "" && column != "namespace" {
+				cell = "<none>"
+			}
+			cells = append(cells, cell)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -14,20 +14,21 @@ limitations under the License.
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/do-community/kubectldoweb"
 
 	"github.com/skratchdot/open-golang/open"
 	"github.com/urfave/cli/v2"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
-const cloudBase = "https://cloud.digitalocean.com/"
-
 type opener func(input string) error
 
 var errHelp = fmt.Errorf("errHelp")
@@ -36,27 +37,75 @@ func main() {
 	runCLI(os.Args)
 }
 
-func newApp(rootCmd cli.ActionFunc) *cli.App {
+func newApp(rootCmd, whichCmd, uiCmd, lsCmd, inventoryCmd, diffCmd cli.ActionFunc) *cli.App {
 	return &cli.App{
 		Name:  "kubectl-doweb",
 		Usage: "a kubectl plugin for opening DigitalOcean resources in a web browser",
-		UsageText: `kubectl doweb <type> <name>
+		UsageText: `kubectl doweb <type> [<name>...]
+   kubectl doweb <type>/<name> [<type>/<name>...]
+   kubectl doweb - < <references>
+   kubectl doweb -f <file|dir|-> [<type>/<name>...]
+   kubectl doweb which <URL|ID>
+   kubectl doweb ui
+   kubectl doweb ls [-A] [-o wide] [--sort-by <column>]
+   kubectl doweb inventory [--format csv|json|markdown]
+   kubectl doweb diff <inventory.json|context> <inventory.json|context>
 
 EXAMPLES:
 
    kubectl doweb service main-load-balancer
    kubectl doweb cluster
+   kubectl doweb statefulset postgres
+   kubectl doweb svc/web node/pool-c0yaq2bd6-95th
+   kubectl doweb svc -l team=payments -A
+   kubectl doweb --pool node pool-c0yaq2bd6-95th
+   kubectl doweb --page insights cluster
+   kubectl doweb -o json svc web
+   kubectl get svc -o name | kubectl doweb -o url -
+   kubectl doweb which https://cloud.digitalocean.com/volumes/506f78a4-e098
+   kubectl doweb --all-contexts which 196128371
+   kubectl doweb --context prod ui
+   kubectl doweb ls -A -o wide --sort-by region
+   kubectl doweb inventory --format csv > inventory.csv
+   kubectl doweb diff before-upgrade.json prod
+   kubectl doweb diff prod-blue prod-green
+   kubectl doweb -f dump.yaml --cluster-id 1b5f1a9e-4d0e-4bd5-a6b4-fa2a7c0a1c2b -o url
 
 SUPPORTED TYPES:
 
-   cluster, node (no), service (svc), persistentvolume (pv), persistentvolumeclaim (pvc)`,
+   cluster, nodepool (np), node (no), pod (po), service (svc), ingress (ing), gateway (gtw), httproute, persistentvolume (pv), persistentvolumeclaim (pvc),
+   deployment (deploy), statefulset (sts), daemonset (ds), replicaset (rs), job`,
 		Action: rootCmd,
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:  "kubeconfig",
-				Usage: "absolute path to the kubeconfig file",
-				Value: defaultKubeconfigPath(),
+		Commands: []*cli.Command{
+			{
+				Name:      "which",
+				Usage:     "find the Kubernetes objects behind a DigitalOcean resource",
+				ArgsUsage: "<cloud.digitalocean.com URL | Droplet ID | Load Balancer ID | Volume ID>",
+				Action:    whichCmd,
+			},
+			{
+				Name:   "ui",
+				Usage:  "browse the DigitalOcean resources of the cluster in a terminal UI that refreshes as they change",
+				Action: uiCmd,
+			},
+			{
+				Name:   "ls",
+				Usage:  "list the nodes, LoadBalancer Services, PersistentVolumes and PersistentVolumeClaims backed by DigitalOcean resources",
+				Action: lsCmd,
 			},
+			{
+				Name:   "inventory",
+				Usage:  "export the DigitalOcean resources of the cluster and the Kubernetes objects they are charged to",
+				Action: inventoryCmd,
+			},
+			{
+				Name:      "diff",
+				Usage:     "compare the DigitalOcean resources of two inventories or two kube contexts",
+				ArgsUsage: "<inventory.json | context> <inventory.json | context>",
+				Action:    diffCmd,
+			},
+		},
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:        "namespace",
 				Usage:       "kubernetes object namespace",
@@ -64,14 +113,74 @@ SUPPORTED TYPES:
 				Aliases:     []string{"n"},
 				DefaultText: "default namespace in kubeconfig",
 			},
-		},
+			&cli.BoolFlag{
+				Name:  "pool",
+				Usage: "open the DOKS node pool of a node instead of its Droplet",
+			},
+			&cli.StringFlag{
+				Name:    "selector",
+				Aliases: []string{"l"},
+				Usage:   "label selector to open every matching object of a type, e.g. -l team=payments",
+			},
+			&cli.StringFlag{
+				Name:  "field-selector",
+				Usage: "field selector to open every matching object of a type, e.g. --field-selector spec.type=LoadBalancer",
+			},
+			&cli.BoolFlag{
+				Name:    "all-namespaces",
+				Aliases: []string{"A"},
+				Usage:   "open the matching objects of a type in every namespace, or list every namespace with ls",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "print the resolved links instead of opening them. One of: url, json, yaml, name, go-template=..., go-template-file=..., jsonpath=..., jsonpath-file=.... With ls, wide adds the region, size and node pool",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "format of the inventory. One of: csv, json, markdown",
+				Value: "json",
+			},
+			&cli.StringFlag{
+				Name:  "sort-by",
+				Usage: "sort the ls table by one of its columns: namespace, name, type, id, url, region, size or pool",
+			},
+			&cli.IntFlag{
+				Name:  "max-tabs",
+				Usage: "ask for confirmation before opening more than this many browser tabs",
+				Value: 10,
+			},
+			&cli.StringSliceFlag{
+				Name:    "filename",
+				Aliases: []string{"f"},
+				Usage:   "resolve the objects of a manifest, a directory of manifests or - for stdin, such as a kubectl get -o yaml dump, without contacting the cluster",
+			},
+			&cli.StringFlag{
+				Name:        "cluster-id",
+				Usage:       "ID of the DOKS cluster the objects given with --filename belong to",
+				DefaultText: "cluster of the kubeconfig",
+			},
+			&cli.BoolFlag{
+				Name:  "all-contexts",
+				Usage: "search the cluster of every context of the kubeconfig concurrently, skipping unreachable and non-DOKS ones",
+			},
+			&cli.DurationFlag{
+				Name:  "context-timeout",
+				Usage: "time to wait for each context with --all-contexts",
+				Value: kubectldoweb.DefaultContextTimeout,
+			},
+			&cli.StringFlag{
+				Name:  "page",
+				Usage: "open a tab of the resource page, e.g. insights, resources, marketplace, nodes, upgrades or settings for a cluster, graphs, console or access for a node and graphs or settings for a service",
+			},
+		}, kubeConfigFlags()...),
 	}
 }
 
 func runCLI(args []string) {
-	app := newApp(newRootCmd(kubectldoweb.Run, open.Run))
+	app := newApp(newRootCmd(kubectldoweb.Run, kubectldoweb.Candidates, open.Run), newWhichCmd(kubectldoweb.Which), newUICmd(kubectldoweb.WatchOverview, open.Run), newLsCmd(kubectldoweb.FetchOverview), newInventoryCmd(kubectldoweb.FetchInventory), newDiffCmd(kubectldoweb.Diff))
 
-	err := app.Run(args)
+	err := app.Run(flagsFirst(app.Flags, args))
 	if err != nil {
 		if err == errHelp || err == kubectldoweb.ErrMissingArgument {
 			app.Run([]string{"", "help"})
@@ -82,37 +191,281 @@ func runCLI(args []string) {
 	}
 }
 
-func newRootCmd(runner kubectldoweb.Runner, opnr opener) cli.ActionFunc {
+// flagsFirst moves flags given after the arguments in front of them, so that
+// flags can be placed anywhere like with kubectl
+func flagsFirst(flags []cli.Flag, args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+
+	boolFlags := map[string]bool{}
+	for _, flag := range flags {
+		if _, ok := flag.(*cli.BoolFlag); ok {
+			for _, name := range flag.Names() {
+				boolFlags[name] = true
+			}
+		}
+	}
+
+	reordered := []string{args[0]}
+	var positional []string
+	rest := args[1:]
+	for i := 0; i < len(rest); i++ {
+		arg := rest[i]
+		if arg == "--" {
+			positional = append(positional, rest[i:]...)
+			break
+		}
+		if arg == "-" || !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		reordered = append(reordered, arg)
+		name := strings.TrimLeft(arg, "-")
+		if !strings.Contains(name, "=") && !boolFlags[name] && i+1 < len(rest) {
+			i++
+			reordered = append(reordered, rest[i])
+		}
+	}
+
+	return append(reordered, positional...)
+}
+
+func newRootCmd(runner kubectldoweb.Runner, candidates kubectldoweb.CandidatesRunner, opnr opener) cli.ActionFunc {
 	return func(c *cli.Context) error {
-		if c.Args().Len() < 1 {
+		filenames := c.StringSlice("filename")
+		if c.Args().Len() < 1 && len(filenames) == 0 {
 			return errHelp
 		}
 
-		kubeConfigPath := c.String("kubeconfig")
-		kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-			&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeConfigPath},
-			&clientcmd.ConfigOverrides{},
-		)
+		kubeConfig, err := kubeConfigFrom(c)
+		if err != nil {
+			return err
+		}
 
 		namespace := c.String("namespace")
-		typ := c.Args().Get(0)
-		name := c.Args().Get(1)
+		// without arguments, every object of the files is resolved
+		var refs []kubectldoweb.Reference
+		stdinRefs := false
+		for _, arg := range c.Args().Slice() {
+			if arg == "-" {
+				stdinRefs = true
+			}
+		}
+		switch {
+		case stdinRefs:
+			refs, err = readStdinReferences(c.Args().Slice(), filenames)
+			if err != nil {
+				return err
+			}
+		case c.Args().Len() > 0:
+			refs, err = kubectldoweb.ParseReferences(c.Args().Slice())
+			if err != nil {
+				return err
+			}
+		}
+
+		output := c.String("output")
+		var printLinks printer
+		if output != "" {
+			printLinks, err = printerFor(output)
+			if err != nil {
+				return err
+			}
+		}
 
-		path, err := runner(c.Context, os.Stderr, kubeConfig, namespace, typ, name)
+		opts := kubectldoweb.RunOptions{
+			Options: kubectldoweb.Options{
+				Pool:          c.Bool("pool"),
+				Page:          c.String("page"),
+				LabelSelector: c.String("selector"),
+				FieldSelector: c.String("field-selector"),
+				AllNamespaces: c.Bool("all-namespaces"),
+			},
+			Filenames:        filenames,
+			ClusterID:        c.String("cluster-id"),
+			AllContexts:      c.Bool("all-contexts"),
+			ContextTimeout:   c.Duration("context-timeout"),
+			ContextOverrides: configOverridesFrom(c),
+		}
+		bulk := opts.Bulk() || (len(filenames) > 0 && len(refs) == 0)
+
+		// once stdin is consumed by the references, questions are asked on the terminal
+		prompts := os.Stdin
+		if stdinRefs {
+			if tty, err := os.Open("/dev/tty"); err == nil {
+				defer tty.Close()
+				prompts = tty
+			}
+		}
+		in := bufio.NewReader(prompts)
+
+		results, err := runner(c.Context, os.Stderr, kubeConfig, namespace, refs, opts)
 		if err != nil {
 			return err
 		}
 
-		url := fmt.Sprintf("%s%s", cloudBase, path)
-		return opnr(url)
+		// a type given without a name is completed by picking one of its objects
+		if !bulk && !opts.AllContexts && len(refs) == 1 && len(results) == 1 &&
+			results[0].Err == kubectldoweb.ErrMissingArgument && isTerminal(prompts) {
+			ref, err := pickReference(c, in, candidates, kubeConfig, namespace, refs[0].Type, opts)
+			if err != nil {
+				return err
+			}
+			refs = []kubectldoweb.Reference{ref}
+			results, err = runner(c.Context, os.Stderr, kubeConfig, ref.Namespace, refs, opts)
+			if err != nil {
+				return err
+			}
+		}
+
+		// a single reference keeps reporting its error as is, e.g. to show the help text
+		if !bulk && len(results) == 1 && results[0].Err != nil {
+			return results[0].Err
+		}
+
+		var links []kubectldoweb.Link
+		failed := 0
+		for _, result := range results {
+			if result.Err == nil {
+				links = append(links, result.Links...)
+				continue
+			}
+
+			// objects selected in bulk that are not backed by DigitalOcean resources are expected
+			if bulk && result.Reference.Name != "" {
+				fmt.Fprintf(os.Stderr, "skipping %s (namespace %s): %s\n", result.Reference, result.Reference.Namespace, result.Err)
+				continue
+			}
+			if result.Context != "" {
+				fmt.Fprintf(os.Stderr, "error: %s (context %s): %s\n", result.Reference, result.Context, result.Err)
+			} else {
+				fmt.Fprintf(os.Stderr, "error: %s: %s\n", result.Reference, result.Err)
+			}
+			failed++
+		}
+
+		if printLinks != nil {
+			if err := printLinks(os.Stdout, kubectldoweb.NewLinkRecordList(results)); err != nil {
+				return err
+			}
+			return resolveFailure(failed, len(results))
+		}
+
+		for _, link := range links {
+			for _, note := range link.Notes {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", link.Title, note)
+			}
+		}
+
+		if len(links) > 1 {
+			links, err = pickLinks(in, os.Stderr, links)
+			if err != nil {
+				return err
+			}
+		}
+
+		maxTabs := c.Int("max-tabs")
+		if maxTabs > 0 && len(links) > maxTabs {
+			ok, err := confirm(in, os.Stderr, fmt.Sprintf("open %d browser tabs?", len(links)))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+		}
+
+		for _, link := range links {
+			if err := opnr(link.URL); err != nil {
+				return err
+			}
+		}
+
+		return resolveFailure(failed, len(results))
+	}
+}
+
+// pickReference lists the objects of typ backed by DigitalOcean resources and
+// lets the user pick one of them
+func pickReference(c *cli.Context, in *bufio.Reader, candidates kubectldoweb.CandidatesRunner, kubeConfig clientcmd.ClientConfig, namespace, typ string, opts kubectldoweb.RunOptions) (kubectldoweb.Reference, error) {
+	refs, err := candidates(c.Context, os.Stderr, kubeConfig, namespace, typ, opts)
+	if err != nil {
+		return kubectldoweb.Reference{}, err
+	}
+	if len(refs) == 0 {
+		return kubectldoweb.Reference{}, fmt.Errorf("no %s backed by DigitalOcean resources found", typ)
 	}
+	return fuzzyPick(in, os.Stderr, refs)
 }
 
-func defaultKubeconfigPath() string {
-	home := homedir.HomeDir()
-	if home == "" {
-		return ""
+// readStdinReferences reads the references of kubectl doweb -
+func readStdinReferences(args, filenames []string) ([]kubectldoweb.Reference, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("- reads references from stdin and cannot be combined with other arguments")
+	}
+	for _, filename := range filenames {
+		if filename == "-" {
+			return nil, fmt.Errorf("stdin cannot be read for both references and --filename")
+		}
+	}
+
+	refs, err := kubectldoweb.ReadReferences(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("reading references from stdin: %w", err)
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("no references were read from stdin")
+	}
+	return refs, nil
+}
+
+func resolveFailure(failed, total int) error {
+	if failed > 0 {
+		return fmt.Errorf("%d of %d references could not be resolved", failed, total)
+	}
+	return nil
+}
+
+// pickLinks lists the resolved links and lets the user choose which ones to open
+func pickLinks(in *bufio.Reader, out io.Writer, links []kubectldoweb.Link) ([]kubectldoweb.Link, error) {
+	for i, link := range links {
+		fmt.Fprintf(out, "%d) %s: %s\n", i+1, link.Title, link.URL)
+	}
+	fmt.Fprint(out, "select the pages to open (e.g. 1,3) or press enter to open all: ")
+
+	line, err := in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" || line == "all" {
+		return links, nil
+	}
+
+	var picked []kubectldoweb.Link
+	for _, field := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		i, err := strconv.Atoi(field)
+		if err != nil || i < 1 || i > len(links) {
+			return nil, fmt.Errorf("invalid selection %s, must be a number between 1 and %d", field, len(links))
+		}
+		picked = append(picked, links[i-1])
+	}
+
+	return picked, nil
+}
+
+// confirm asks a yes/no question, defaulting to no
+func confirm(in *bufio.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+
+	line, err := in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
 	}
 
-	return filepath.Join(home, ".kube", "config")
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes", nil
 }
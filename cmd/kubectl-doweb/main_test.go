@@ -0,0 +1,170 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/do-community/kubectldoweb"
+)
+
+func Test_flagsFirst(t *testing.T) {
+	app := newApp(nil, nil, nil, nil, nil, nil)
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "no arguments",
+			args: []string{},
+			want: []string{},
+		},
+		{
+			name: "flags after the type",
+			args: []string{"doweb", "svc", "-l", "a=b", "-A"},
+			want: []string{"doweb", "-l", "a=b", "-A", "svc"},
+		},
+		{
+			name: "stdin as the value of a flag",
+			args: []string{"doweb", "-f", "-", "svc/web"},
+			want: []string{"doweb", "-f", "-", "svc/web"},
+		},
+		{
+			name: "stdin as an argument",
+			args: []string{"doweb", "-", "-o", "url"},
+			want: []string{"doweb", "-o", "url", "-"},
+		},
+		{
+			name: "value given with =",
+			args: []string{"doweb", "svc", "web", "-o=json"},
+			want: []string{"doweb", "-o=json", "svc", "web"},
+		},
+		{
+			name: "bool flag followed by an argument",
+			args: []string{"doweb", "which", "-A", "id"},
+			want: []string{"doweb", "-A", "which", "id"},
+		},
+		{
+			name: "long bool flag",
+			args: []string{"doweb", "node", "--pool", "pool-1"},
+			want: []string{"doweb", "--pool", "node", "pool-1"},
+		},
+		{
+			name: "arguments after --",
+			args: []string{"doweb", "svc", "--", "-x", "-o", "url"},
+			want: []string{"doweb", "svc", "--", "-x", "-o", "url"},
+		},
+		{
+			name: "flag missing its value",
+			args: []string{"doweb", "svc", "-n"},
+			want: []string{"doweb", "-n", "svc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flagsFirst(app.Flags, tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flagsFirst() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pickLinks(t *testing.T) {
+	links := []kubectldoweb.Link{{Title: "one"}, {Title: "two"}, {Title: "three"}}
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "enter",
+			input: "\n",
+			want:  []string{"one", "two", "three"},
+		},
+		{
+			name:  "all",
+			input: "all\n",
+			want:  []string{"one", "two", "three"},
+		},
+		{
+			name:  "comma separated",
+			input: "1,3\n",
+			want:  []string{"one", "three"},
+		},
+		{
+			name:  "space separated without a newline",
+			input: "3 2",
+			want:  []string{"three", "two"},
+		},
+		{
+			name:    "out of range",
+			input:   "4\n",
+			wantErr: true,
+		},
+		{
+			name:    "not a number",
+			input:   "one\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pickLinks(bufio.NewReader(strings.NewReader(tt.input)), ioutil.Discard, links)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("pickLinks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var titles []string
+			for _, link := range got {
+				titles = append(titles, link.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.want) {
+				t.Errorf("pickLinks() = %v, want %v", titles, tt.want)
+			}
+		})
+	}
+}
+
+func Test_confirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "y\n", want: true},
+		{input: "YES\n", want: true},
+		{input: " yes ", want: true},
+		{input: "\n", want: false},
+		{input: "n\n", want: false},
+		{input: "yep\n", want: false},
+		{input: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := confirm(bufio.NewReader(strings.NewReader(tt.input)), ioutil.Discard, "open?")
+			if err != nil {
+				t.Fatalf("confirm() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("confirm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
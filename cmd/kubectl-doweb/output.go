@@ -0,0 +1,150 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/do-community/kubectldoweb"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// outputFormats are the values accepted by --output, mapped to their printers
+var outputFormats = map[string]printer{
+	"url":  printURLs,
+	"name": printNames,
+	"json": printJSON,
+	"yaml": printYAML,
+}
+
+func printURLs(w io.Writer, list kubectldoweb.LinkRecordList) error {
+	for _, record := range list.Items {
+		fmt.Fprintln(w, record.URL)
+	}
+	return nil
+}
+
+func printNames(w io.Writer, list kubectldoweb.LinkRecordList) error {
+	for _, record := range list.Items {
+		if record.ID == "" {
+			fmt.Fprintln(w, record.Type)
+			continue
+		}
+		fmt.Fprintf(w, "%s/%s\n", record.Type, record.ID)
+	}
+	return nil
+}
+
+func printJSON(w io.Writer, list kubectldoweb.LinkRecordList) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(list)
+}
+
+func printYAML(w io.Writer, list kubectldoweb.LinkRecordList) error {
+	out, err := yaml.Marshal(list)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+type printer func(w io.Writer, list kubectldoweb.LinkRecordList) error
+
+// printerFor returns the printer of an output format. Like kubectl, template
+// formats are given as <format>=<template> or <format>-file=<path>.
+func printerFor(format string) (printer, error) {
+	if p, ok := outputFormats[format]; ok {
+		return p, nil
+	}
+
+	parts := strings.SplitN(format, "=", 2)
+	if len(parts) == 2 {
+		name, arg := parts[0], parts[1]
+		if strings.HasSuffix(name, "-file") {
+			content, err := ioutil.ReadFile(arg)
+			if err != nil {
+				return nil, err
+			}
+			name, arg = strings.TrimSuffix(name, "-file"), string(content)
+		}
+
+		switch name {
+		case "go-template":
+			return goTemplatePrinter(arg)
+		case "jsonpath":
+			return jsonPathPrinter(arg)
+		}
+	}
+
+	names := make([]string, 0, len(outputFormats))
+	for name := range outputFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown output format %s, must be one of: %s, go-template=..., go-template-file=..., jsonpath=..., jsonpath-file=...", format, strings.Join(names, ", "))
+}
+
+// goTemplatePrinter executes the template once per link record, e.g.
+// {{.Kubernetes.Name}} {{.URL}}, printing each on its own line
+func goTemplatePrinter(text string) (printer, error) {
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing go-template: %s", err)
+	}
+
+	return func(w io.Writer, list kubectldoweb.LinkRecordList) error {
+		for _, record := range list.Items {
+			if err := tmpl.Execute(w, record); err != nil {
+				return fmt.Errorf("error executing go-template: %s", err)
+			}
+			fmt.Fprintln(w)
+		}
+		return nil
+	}, nil
+}
+
+// jsonPathPrinter evaluates the template against the json document of the
+// link list, e.g. {.items[*].url}, like kubectl get does for lists
+func jsonPathPrinter(text string) (printer, error) {
+	parser := jsonpath.New("output").AllowMissingKeys(true)
+	if err := parser.Parse(text); err != nil {
+		return nil, fmt.Errorf("error parsing jsonpath %s: %s", text, err)
+	}
+
+	return func(w io.Writer, list kubectldoweb.LinkRecordList) error {
+		data, err := json.Marshal(list)
+		if err != nil {
+			return err
+		}
+
+		var document interface{}
+		if err := json.Unmarshal(data, &document); err != nil {
+			return err
+		}
+
+		if err := parser.Execute(w, document); err != nil {
+			return fmt.Errorf("error executing jsonpath %s: %s", text, err)
+		}
+		return nil
+	}, nil
+}
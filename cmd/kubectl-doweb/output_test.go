@@ -0,0 +1,119 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/do-community/kubectldoweb"
+)
+
+func Test_printerFor(t *testing.T) {
+	list := kubectldoweb.LinkRecordList{
+		APIVersion: kubectldoweb.RecordListVersion,
+		Kind:       "LinkList",
+		Items: []kubectldoweb.LinkRecord{
+			{
+				Kubernetes: kubectldoweb.KubernetesRecord{Kind: "Service", Namespace: "default", Name: "web"},
+				Type:       "load_balancer",
+				ID:         "lb-id",
+				URL:        "https://cloud.digitalocean.com/networking/load_balancers/lb-id",
+			},
+			{
+				Kubernetes: kubectldoweb.KubernetesRecord{Kind: "Cluster"},
+				Type:       "kubernetes_cluster",
+				URL:        "https://cloud.digitalocean.com/kubernetes/clusters",
+			},
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "doweb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	templateFile := filepath.Join(dir, "template")
+	if err := ioutil.WriteFile(templateFile, []byte("{{.Type}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			format: "url",
+			want:   "https://cloud.digitalocean.com/networking/load_balancers/lb-id\nhttps://cloud.digitalocean.com/kubernetes/clusters\n",
+		},
+		{
+			format: "name",
+			want:   "load_balancer/lb-id\nkubernetes_cluster\n",
+		},
+		{
+			format: "go-template={{.Kubernetes.Name}} {{.ID}}",
+			want:   "web lb-id\n \n",
+		},
+		{
+			format: "go-template-file=" + templateFile,
+			want:   "load_balancer\nkubernetes_cluster\n",
+		},
+		{
+			format: "jsonpath={.items[*].type}",
+			want:   "load_balancer kubernetes_cluster",
+		},
+		{
+			format: "jsonpath={.items[0].kubernetes.whomst}",
+			want:   "",
+		},
+		{
+			format:  "go-template={{.Whomst}}",
+			wantErr: true,
+		},
+		{
+			format:  "go-template={{",
+			wantErr: true,
+		},
+		{
+			format:  "jsonpath={.items[",
+			wantErr: true,
+		},
+		{
+			format:  "go-template-file=" + filepath.Join(dir, "missing"),
+			wantErr: true,
+		},
+		{
+			format:  "wide",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			p, err := printerFor(tt.format)
+			if err == nil {
+				var out bytes.Buffer
+				if err = p(&out, list); err == nil && out.String() != tt.want {
+					t.Errorf("printerFor() printed %q, want %q", out.String(), tt.want)
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("printerFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
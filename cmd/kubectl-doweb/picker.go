@@ -0,0 +1,120 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/do-community/kubectldoweb"
+)
+
+// maxListed is how many candidates fuzzyPick shows at once
+const maxListed = 20
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// fuzzyPick lists refs and lets the user narrow them down by typing part of
+// their name, until one is picked by its number or, with enter, the first one
+func fuzzyPick(in *bufio.Reader, out io.Writer, refs []kubectldoweb.Reference) (kubectldoweb.Reference, error) {
+	query := ""
+	for {
+		matches := fuzzyFilter(refs, query)
+		for i, ref := range matches {
+			if i == maxListed {
+				fmt.Fprintf(out, "... and %d more, type to filter\n", len(matches)-maxListed)
+				break
+			}
+			fmt.Fprintf(out, "%d) %s\n", i+1, ref.Name)
+		}
+		if len(matches) == 0 {
+			fmt.Fprintf(out, "nothing matches %q\n", query)
+		}
+		fmt.Fprint(out, "type to filter, then pick a number or press enter for the first one: ")
+
+		line, err := in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			if err == io.EOF {
+				return kubectldoweb.Reference{}, fmt.Errorf("no %s was picked", refs[0].Type)
+			}
+			return kubectldoweb.Reference{}, err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" && len(matches) > 0 {
+			return matches[0], nil
+		}
+		if i, err := strconv.Atoi(line); err == nil && i >= 1 && i <= len(matches) && i <= maxListed {
+			return matches[i-1], nil
+		}
+		query = line
+	}
+}
+
+// fuzzyFilter keeps the refs whose name contains the letters of query in
+// order, best matches first: the ones where the letters are closest together
+func fuzzyFilter(refs []kubectldoweb.Reference, query string) []kubectldoweb.Reference {
+	type scored struct {
+		ref   kubectldoweb.Reference
+		score int
+	}
+
+	var matches []scored
+	for _, ref := range refs {
+		if score, ok := fuzzyScore(strings.ToLower(ref.Name), strings.ToLower(query)); ok {
+			matches = append(matches, scored{ref, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score < matches[j].score })
+
+	filtered := make([]kubectldoweb.Reference, 0, len(matches))
+	for _, match := range matches {
+		filtered = append(filtered, match.ref)
+	}
+	return filtered
+}
+
+// fuzzyScore reports whether name contains the letters of query in order, and
+// how many letters of name are skipped between the first and the last one
+func fuzzyScore(name, query string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	start, score, q := -1, 0, []rune(query)
+	for i, r := range []rune(name) {
+		if len(q) == 0 {
+			break
+		}
+		if r != q[0] {
+			if start >= 0 {
+				score++
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		q = q[1:]
+	}
+	return score, len(q) == 0
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/do-community/kubectldoweb"
+)
+
+func refNames(refs []kubectldoweb.Reference) []string {
+	var names []string
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+func pickerRefs(names ...string) []kubectldoweb.Reference {
+	refs := make([]kubectldoweb.Reference, 0, len(names))
+	for _, name := range names {
+		refs = append(refs, kubectldoweb.Reference{Type: "svc", Name: name})
+	}
+	return refs
+}
+
+func Test_fuzzyScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		want      int
+		wantMatch bool
+	}{
+		{name: "web", query: "", want: 0, wantMatch: true},
+		{name: "web", query: "web", want: 0, wantMatch: true},
+		{name: "api-web", query: "web", want: 0, wantMatch: true},
+		{name: "web", query: "wb", want: 1, wantMatch: true},
+		{name: "worker-b", query: "wb", want: 6, wantMatch: true},
+		{name: "web", query: "bw", wantMatch: false},
+		{name: "web", query: "webs", wantMatch: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.query, func(t *testing.T) {
+			got, ok := fuzzyScore(tt.name, tt.query)
+			if ok != tt.wantMatch {
+				t.Errorf("fuzzyScore() match = %v, want %v", ok, tt.wantMatch)
+				return
+			}
+			if ok && got != tt.want {
+				t.Errorf("fuzzyScore() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fuzzyFilter(t *testing.T) {
+	refs := pickerRefs("worker-b", "web", "database", "api-web")
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: []string{"worker-b", "web", "database", "api-web"}},
+		{query: "wb", want: []string{"web", "api-web", "worker-b"}},
+		{query: "WEB", want: []string{"web", "api-web", "worker-b"}},
+		{query: "db", want: []string{"database"}},
+		{query: "zz", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := refNames(fuzzyFilter(refs, tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fuzzyFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fuzzyPick(t *testing.T) {
+	refs := pickerRefs("worker-b", "web", "database", "api-web")
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "enter picks the first one", input: "\n", want: "worker-b"},
+		{name: "number", input: "3\n", want: "database"},
+		{name: "filter then enter", input: "wb\n\n", want: "web"},
+		{name: "filter then number", input: "web\n2\n", want: "api-web"},
+		{name: "number past the matches is a query", input: "db\n2\ndb\n1\n", want: "database"},
+		{name: "nothing picked", input: "zz\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fuzzyPick(bufio.NewReader(strings.NewReader(tt.input)), ioutil.Discard, refs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("fuzzyPick() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.Name != tt.want {
+				t.Errorf("fuzzyPick() = %s, want %s", got.Name, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,421 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/do-community/kubectldoweb"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+	"github.com/urfave/cli/v2"
+)
+
+const uiHelp = "tab: next pane  /: filter  enter: open  y: copy URL  q: quit"
+
+// uiRow is a line of a pane, linked to the DigitalOcean resources it shows
+type uiRow struct {
+	cells   []string
+	links   []kubectldoweb.Link
+	details string
+}
+
+// uiPane is a table listing one kind of DigitalOcean resource of the overview
+type uiPane struct {
+	title   string
+	columns []string
+	rows    func(*kubectldoweb.Overview) []uiRow
+
+	table   *tview.Table
+	visible []uiRow
+}
+
+func newUICmd(watch kubectldoweb.OverviewWatcher, opnr opener) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if c.Args().Len() > 0 {
+			return fmt.Errorf("ui does not take arguments")
+		}
+
+		opts := kubectldoweb.RunOptions{
+			Filenames:   c.StringSlice("filename"),
+			ClusterID:   c.String("cluster-id"),
+			AllContexts: c.Bool("all-contexts"),
+		}
+		kubeConfig, err := kubeConfigFrom(c)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(c.Context)
+		defer cancel()
+		// progress messages would corrupt the screen
+		updates, err := watch(ctx, ioutil.Discard, kubeConfig, opts)
+		if err != nil {
+			return err
+		}
+
+		return newUI(opnr).run(updates)
+	}
+}
+
+type ui struct {
+	app     *tview.Application
+	panes   []*uiPane
+	focused int
+	filter  *tview.InputField
+	details *tview.TextView
+	status  *tview.TextView
+	open    opener
+
+	overview  *kubectldoweb.Overview
+	refreshed time.Time
+}
+
+func newUI(opnr opener) *ui {
+	u := &ui{
+		app:     tview.NewApplication(),
+		panes:   uiPanes(),
+		filter:  tview.NewInputField().SetLabel("filter: "),
+		details: tview.NewTextView().SetDynamicColors(true).SetWrap(true),
+		status:  tview.NewTextView().SetDynamicColors(true),
+		open:    opnr,
+	}
+	u.details.SetBorder(true).SetTitle(" details ")
+
+	left := tview.NewFlex().SetDirection(tview.FlexRow)
+	for i, pane := range u.panes {
+		i, pane := i, pane
+		pane.table = tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+		pane.table.SetBorder(true).SetTitle(" " + pane.title + " ")
+		pane.table.SetSelectionChangedFunc(func(row, column int) {
+			if i == u.focused {
+				u.showDetails()
+			}
+		})
+		pane.table.SetSelectedFunc(func(row, column int) { u.openSelected() })
+
+		// the cluster fits on a line, the other panes share the rest of the screen
+		if i == 0 {
+			left.AddItem(pane.table, 4, 0, false)
+		} else {
+			left.AddItem(pane.table, 0, 1, false)
+		}
+	}
+
+	u.filter.SetChangedFunc(func(string) { u.render() })
+	u.filter.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			u.filter.SetText("")
+		}
+		u.focus(u.focused)
+	})
+
+	body := tview.NewFlex().
+		AddItem(left, 0, 3, true).
+		AddItem(u.details, 0, 2, false)
+	root := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(body, 0, 1, true).
+		AddItem(u.filter, 1, 0, false).
+		AddItem(u.status, 1, 0, false)
+
+	u.app.SetRoot(root, true).SetInputCapture(u.handleKey)
+	u.focus(0)
+	u.setStatus("loading...")
+	return u
+}
+
+func (u *ui) run(updates <-chan kubectldoweb.OverviewUpdate) error {
+	go func() {
+		for update := range updates {
+			update := update
+			u.app.QueueUpdateDraw(func() {
+				if update.Err != nil {
+					u.setStatus(fmt.Sprintf("[red]refresh failed: %s", tview.Escape(update.Err.Error())))
+					return
+				}
+				u.overview, u.refreshed = update.Overview, time.Now()
+				u.render()
+				u.setStatus("")
+			})
+		}
+	}()
+
+	return u.app.Run()
+}
+
+func (u *ui) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if u.app.GetFocus() == u.filter {
+		return event
+	}
+
+	switch event.Key() {
+	case tcell.KeyTab:
+		u.focus((u.focused + 1) % len(u.panes))
+		return nil
+	case tcell.KeyBacktab:
+		u.focus((u.focused + len(u.panes) - 1) % len(u.panes))
+		return nil
+	case tcell.KeyEscape:
+		u.filter.SetText("")
+		return nil
+	}
+
+	switch event.Rune() {
+	case 'q':
+		u.app.Stop()
+		return nil
+	case '/':
+		u.app.SetFocus(u.filter)
+		return nil
+	case 'y':
+		u.copySelected()
+		return nil
+	}
+	return event
+}
+
+func (u *ui) focus(i int) {
+	u.focused = i
+	for j, pane := range u.panes {
+		pane.table.SetSelectable(j == i, false)
+	}
+	u.app.SetFocus(u.panes[i].table)
+	u.showDetails()
+}
+
+// render fills the panes from the overview, keeping the selected rows selected
+func (u *ui) render() {
+	if u.overview == nil {
+		return
+	}
+
+	query := strings.ToLower(u.filter.GetText())
+	for _, pane := range u.panes {
+		selected := ""
+		if row, ok := pane.selected(); ok {
+			selected = strings.Join(row.cells, "\t")
+		}
+
+		pane.visible = pane.visible[:0]
+		for _, row := range pane.rows(u.overview) {
+			if strings.Contains(strings.ToLower(strings.Join(row.cells, " ")), query) {
+				pane.visible = append(pane.visible, row)
+			}
+		}
+
+		pane.table.Clear()
+		for i, column := range pane.columns {
+			pane.table.SetCell(0, i, tview.NewTableCell(column).SetSelectable(false).SetTextColor(tcell.ColorYellow))
+		}
+		selectedRow := 1
+		for i, row := range pane.visible {
+			for j, cell := range row.cells {
+				pane.table.SetCell(i+1, j, tview.NewTableCell(tview.Escape(cell)).SetExpansion(1))
+			}
+			if strings.Join(row.cells, "\t") == selected {
+				selectedRow = i + 1
+			}
+		}
+		pane.table.Select(selectedRow, 0)
+	}
+	u.showDetails()
+}
+
+// selected returns the row of the pane under the cursor
+func (p *uiPane) selected() (uiRow, bool) {
+	if p.table == nil {
+		return uiRow{}, false
+	}
+	row, _ := p.table.GetSelection()
+	if row < 1 || row > len(p.visible) {
+		return uiRow{}, false
+	}
+	return p.visible[row-1], true
+}
+
+func (u *ui) showDetails() {
+	row, ok := u.panes[u.focused].selected()
+	if !ok {
+		u.details.SetText("")
+		return
+	}
+	u.details.SetText(row.details).ScrollToBeginning()
+}
+
+func (u *ui) selectedURL() (string, bool) {
+	row, ok := u.panes[u.focused].selected()
+	if !ok || len(row.links) == 0 {
+		u.setStatus("[red]nothing to open on this row")
+		return "", false
+	}
+	return row.links[0].URL, true
+}
+
+func (u *ui) openSelected() {
+	url, ok := u.selectedURL()
+	if !ok {
+		return
+	}
+	if err := u.open(url); err != nil {
+		u.setStatus(fmt.Sprintf("[red]could not open %s: %s", url, tview.Escape(err.Error())))
+		return
+	}
+	u.setStatus("opened " + url)
+}
+
+func (u *ui) copySelected() {
+	url, ok := u.selectedURL()
+	if !ok {
+		return
+	}
+	if err := clipboard.WriteAll(url); err != nil {
+		u.setStatus(fmt.Sprintf("[red]could not copy %s: %s", url, tview.Escape(err.Error())))
+		return
+	}
+	u.setStatus("copied " + url)
+}
+
+// setStatus shows msg, or the time of the last refresh, next to the key bindings
+func (u *ui) setStatus(msg string) {
+	if msg == "" && !u.refreshed.IsZero() {
+		msg = "refreshed at " + u.refreshed.Format("15:04:05")
+	}
+	u.status.SetText(fmt.Sprintf("%s  [gray]%s", msg, uiHelp))
+}
+
+func uiPanes() []*uiPane {
+	return []*uiPane{
+		{
+			title:   "Cluster",
+			columns: []string{"ID", "URL"},
+			rows: func(o *kubectldoweb.Overview) []uiRow {
+				details := tview.Escape(strings.Join(o.Notes, "\n"))
+				if o.Cluster.ID == "" {
+					return []uiRow{{cells: []string{"unknown", ""}, details: details}}
+				}
+				return []uiRow{{
+					cells:   []string{o.Cluster.ID, o.Cluster.URL},
+					links:   []kubectldoweb.Link{o.Cluster},
+					details: linkDetails(o.Cluster) + details,
+				}}
+			},
+		},
+		{
+			title:   "Node pools",
+			columns: []string{"NODE POOL", "ID", "REGION"},
+			rows: func(o *kubectldoweb.Overview) []uiRow {
+				var rows []uiRow
+				for _, pool := range o.NodePools {
+					rows = append(rows, uiRow{
+						cells:   []string{pool.Title, pool.ID, pool.Region},
+						links:   []kubectldoweb.Link{pool},
+						details: linkDetails(pool),
+					})
+				}
+				return rows
+			},
+		},
+		{
+			title:   "Nodes and Droplets",
+			columns: []string{"NODE", "DROPLET", "REGION", "NODE POOL"},
+			rows: func(o *kubectldoweb.Overview) []uiRow {
+				var rows []uiRow
+				for _, entry := range o.Nodes {
+					pool := ""
+					if len(entry.Links) > 1 {
+						pool = entry.Links[1].ID
+					}
+					droplet := entry.Links[0]
+					rows = append(rows, entryRow(entry, entry.Name, droplet.ID, droplet.Region, pool))
+				}
+				return rows
+			},
+		},
+		{
+			title:   "LoadBalancer Services",
+			columns: []string{"NAMESPACE", "SERVICE", "LOAD BALANCER"},
+			rows: func(o *kubectldoweb.Overview) []uiRow {
+				var rows []uiRow
+				for _, entry := range o.LoadBalancers {
+					id := "<pending>"
+					if len(entry.Links) > 0 {
+						id = entry.Links[0].ID
+					}
+					rows = append(rows, entryRow(entry, entry.Namespace, entry.Name, id))
+				}
+				return rows
+			},
+		},
+		{
+			title:   "Volumes",
+			columns: []string{"PERSISTENTVOLUME", "CLAIM", "VOLUME", "REGION"},
+			rows: func(o *kubectldoweb.Overview) []uiRow {
+				var rows []uiRow
+				for _, entry := range o.Volumes {
+					volume := entry.Links[0]
+					id := volume.ID
+					if id == "" {
+						id = "<unknown>"
+					}
+					rows = append(rows, entryRow(entry, entry.Name, entry.Claim, id, volume.Region))
+				}
+				return rows
+			},
+		},
+	}
+}
+
+func entryRow(entry kubectldoweb.OverviewEntry, cells ...string) uiRow {
+	var details strings.Builder
+	fmt.Fprintf(&details, "[yellow]%s[-] %s\n", entry.Kind, tview.Escape(objectName(entry.Namespace, entry.Name)))
+	if entry.Claim != "" {
+		fmt.Fprintf(&details, "claimed by %s\n", tview.Escape(entry.Claim))
+	}
+	if entry.Err != nil {
+		fmt.Fprintf(&details, "[red]%s[-]\n", tview.Escape(entry.Err.Error()))
+	}
+	for _, link := range entry.Links {
+		details.WriteString("\n" + linkDetails(link))
+	}
+
+	return uiRow{cells: cells, links: entry.Links, details: details.String()}
+}
+
+func linkDetails(link kubectldoweb.Link) string {
+	var details strings.Builder
+	fmt.Fprintf(&details, "[yellow]%s[-]\n", tview.Escape(link.Title))
+	if link.ID != "" {
+		fmt.Fprintf(&details, "ID:     %s\n", tview.Escape(link.ID))
+	}
+	if link.Region != "" {
+		fmt.Fprintf(&details, "Region: %s\n", tview.Escape(link.Region))
+	}
+	fmt.Fprintf(&details, "URL:    %s\n", tview.Escape(link.URL))
+	for _, note := range link.Notes {
+		fmt.Fprintf(&details, "note:   %s\n", tview.Escape(note))
+	}
+	return details.String()
+}
+
+func objectName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
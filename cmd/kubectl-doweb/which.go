@@ -0,0 +1,75 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/do-community/kubectldoweb"
+	"github.com/urfave/cli/v2"
+)
+
+func newWhichCmd(which kubectldoweb.WhichRunner) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if c.Args().Len() != 1 {
+			return fmt.Errorf("which takes a single control panel URL or resource ID")
+		}
+
+		opts := kubectldoweb.RunOptions{
+			Filenames:        c.StringSlice("filename"),
+			ClusterID:        c.String("cluster-id"),
+			AllContexts:      c.Bool("all-contexts"),
+			ContextTimeout:   c.Duration("context-timeout"),
+			ContextOverrides: configOverridesFrom(c),
+		}
+		kubeConfig, err := kubeConfigFrom(c)
+		if err != nil {
+			return err
+		}
+
+		matches, err := which(c.Context, os.Stderr, kubeConfig, c.Args().First(), opts)
+		if err != nil {
+			return err
+		}
+
+		if len(matches) == 0 {
+			fmt.Fprintf(os.Stderr, "No resources found for %s.\n", c.Args().First())
+			return nil
+		}
+		return printMatches(os.Stdout, matches)
+	}
+}
+
+// printMatches prints matches like kubectl get --all-namespaces prints
+// objects of several kinds, prefixed by their context when they have one
+func printMatches(w io.Writer, matches []kubectldoweb.Match) error {
+	withContext := matches[0].Context != ""
+
+	tw := tabwriter.NewWriter(w, 6, 4, 3, ' ', 0)
+	if withContext {
+		fmt.Fprint(tw, "CONTEXT\t")
+	}
+	fmt.Fprintln(tw, "NAMESPACE\tNAME\tREASON")
+	for _, match := range matches {
+		if withContext {
+			fmt.Fprintf(tw, "%s\t", match.Context)
+		}
+		fmt.Fprintf(tw, "%s\t%s/%s\t%s\n", match.Namespace, strings.ToLower(match.Kind), match.Name, match.Reason)
+	}
+	return tw.Flush()
+}
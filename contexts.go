@@ -0,0 +1,245 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DefaultContextTimeout bounds the search of a single kube context when searching all of them
+const DefaultContextTimeout = 10 * time.Second
+
+// SkippedContext is a kube context that could not be searched, such as an
+// unreachable or non-DOKS cluster
+type SkippedContext struct {
+	Context string
+	Err     error
+}
+
+// contextSearch searches the cluster of a kube context, whose requests are bound by ctx
+type contextSearch func(ctx context.Context, name string, kubeConfig clientcmd.ClientConfig) error
+
+// searchContexts runs search concurrently against every context of kubeConfig
+// that points at a reachable DOKS cluster, each with its own timeout and the
+// overrides of the command line. It fails when no context could be searched.
+func searchContexts(ctx context.Context, kubeConfig clientcmd.ClientConfig, overrides *clientcmd.ConfigOverrides, timeout time.Duration, search contextSearch) ([]SkippedContext, error) {
+	rawConfig, err := kubeConfig.RawConfig()
+	if err != nil {
+		return nil, err
+	}
+	if len(rawConfig.Contexts) == 0 {
+		return nil, fmt.Errorf("the kube config does not have any context")
+	}
+	if timeout <= 0 {
+		timeout = DefaultContextTimeout
+	}
+	// requests that do not take a context, such as discovery, are bound by the client timeout
+	overrides = contextOverrides(overrides, timeout)
+
+	names := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			contextConfig := clientcmd.NewNonInteractiveClientConfig(rawConfig, name, overrides, nil)
+			if err := checkContext(contextConfig); err != nil {
+				errs[i] = err
+				return
+			}
+			errs[i] = search(ctx, name, contextConfig)
+		}(i, name)
+	}
+	wg.Wait()
+
+	var skipped []SkippedContext
+	for i, err := range errs {
+		if err != nil {
+			skipped = append(skipped, SkippedContext{Context: names[i], Err: err})
+		}
+	}
+	if len(skipped) == len(names) {
+		return skipped, fmt.Errorf("none of the %d contexts of the kube config could be searched", len(names))
+	}
+	return skipped, nil
+}
+
+// contextOverrides returns the overrides of the command line that do not
+// depend on the context, the request timeout and impersonation, for every
+// context searched. Requests are bound by timeout unless a request timeout
+// was given.
+func contextOverrides(overrides *clientcmd.ConfigOverrides, timeout time.Duration) *clientcmd.ConfigOverrides {
+	contextOverrides := &clientcmd.ConfigOverrides{}
+	if overrides != nil {
+		contextOverrides.Timeout = overrides.Timeout
+		contextOverrides.AuthInfo.Impersonate = overrides.AuthInfo.Impersonate
+		contextOverrides.AuthInfo.ImpersonateGroups = overrides.AuthInfo.ImpersonateGroups
+	}
+
+	if timeout > 0 && (contextOverrides.Timeout == "" || contextOverrides.Timeout == "0") {
+		contextOverrides.Timeout = timeout.String()
+	}
+	return contextOverrides
+}
+
+// checkContext fails unless the context points at a DOKS cluster that can be reached
+func checkContext(kubeConfig clientcmd.ClientConfig) error {
+	clientConfig, err := kubeConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	if _, err := (&DOCloudPather{clientConfig: clientConfig}).clusterID(); err != nil {
+		return err
+	}
+
+	clientset, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+	_, err = clientset.Discovery().ServerVersion()
+	return err
+}
+
+// resolvedIn reports whether ref has a result. Results carry the namespace
+// they were resolved in, which refs without a namespace leave to the context.
+func resolvedIn(ref Reference, results []Result) bool {
+	for _, result := range results {
+		resolved := result.Reference
+		if resolved.Type == ref.Type && resolved.Name == ref.Name && (ref.Namespace == "" || resolved.Namespace == ref.Namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveAllContexts resolves refs in the cluster of every context of
+// kubeConfig. Objects missing from a cluster are left out, and references
+// found in none of them are reported as failed.
+func ResolveAllContexts(ctx context.Context, kubeConfig clientcmd.ClientConfig, namespace string, refs []Reference, opts RunOptions) ([]Result, []SkippedContext, error) {
+	if len(opts.Filenames) > 0 {
+		return nil, nil, fmt.Errorf("objects read from files cannot be resolved in all contexts")
+	}
+	opts.AllContexts = false
+
+	var mu sync.Mutex
+	byContext := map[string][]Result{}
+	skipped, err := searchContexts(ctx, kubeConfig, opts.ContextOverrides, opts.ContextTimeout, func(ctx context.Context, name string, contextConfig clientcmd.ClientConfig) error {
+		results, err := Run(ctx, ioutil.Discard, contextConfig, namespace, refs, opts)
+		if err != nil {
+			return err
+		}
+
+		found := results[:0]
+		for _, result := range results {
+			if apierrors.IsNotFound(result.Err) {
+				continue
+			}
+			result.Context = name
+			found = append(found, result)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		byContext[name] = found
+		return nil
+	})
+	if err != nil {
+		return nil, skipped, err
+	}
+
+	names := make([]string, 0, len(byContext))
+	for name := range byContext {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results []Result
+	for _, name := range names {
+		results = append(results, byContext[name]...)
+	}
+
+	if !opts.Bulk() {
+		for _, ref := range refs {
+			if !resolvedIn(ref, results) {
+				results = append(results, Result{Reference: ref, Err: fmt.Errorf("not found in any of the %d searched contexts", len(names))})
+			}
+		}
+	}
+
+	return results, skipped, nil
+}
+
+// WhichAllContexts looks target up in the cluster of every context of kubeConfig
+func WhichAllContexts(ctx context.Context, kubeConfig clientcmd.ClientConfig, target Target, opts RunOptions) ([]Match, []SkippedContext, error) {
+	if len(opts.Filenames) > 0 {
+		return nil, nil, fmt.Errorf("objects read from files cannot be searched in all contexts")
+	}
+	opts.AllContexts = false
+
+	var mu sync.Mutex
+	byContext := map[string][]Match{}
+	skipped, err := searchContexts(ctx, kubeConfig, opts.ContextOverrides, opts.ContextTimeout, func(ctx context.Context, name string, contextConfig clientcmd.ClientConfig) error {
+		client, _, err := clientFor(ioutil.Discard, contextConfig, "", opts)
+		if err != nil {
+			return err
+		}
+
+		matches, err := client.Which(ctx, target)
+		if err != nil {
+			return err
+		}
+		for i := range matches {
+			matches[i].Context = name
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		byContext[name] = matches
+		return nil
+	})
+	if err != nil {
+		return nil, skipped, err
+	}
+
+	names := make([]string, 0, len(byContext))
+	for name := range byContext {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var matches []Match
+	for _, name := range names {
+		matches = append(matches, byContext[name]...)
+	}
+	return matches, skipped, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_searchContexts(t *testing.T) {
+	rawConfig := clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"kind": {Server: "https://127.0.0.1:6443"},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"user": {Token: "token"},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"kind":    {Cluster: "kind", AuthInfo: "user"},
+			"missing": {Cluster: "whomst", AuthInfo: "user"},
+		},
+		CurrentContext: "kind",
+	}
+	kubeConfig := clientcmd.NewDefaultClientConfig(rawConfig, &clientcmd.ConfigOverrides{})
+
+	searched := false
+	skipped, err := searchContexts(context.TODO(), kubeConfig, nil, time.Second, func(ctx context.Context, name string, kubeConfig clientcmd.ClientConfig) error {
+		searched = true
+		return nil
+	})
+	if err == nil {
+		t.Errorf("searchContexts() did not fail without any searchable context")
+	}
+	if searched {
+		t.Errorf("searchContexts() searched a context that is not a reachable DOKS cluster")
+	}
+
+	var got []string
+	for _, s := range skipped {
+		got = append(got, s.Context)
+	}
+	if want := []string{"kind", "missing"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("searchContexts() skipped = %v, want %v", got, want)
+	}
+}
+
+func Test_contextOverrides(t *testing.T) {
+	rawConfig := clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"main":  {Server: "https://main" + hostnameSuffix},
+			"other": {Server: "https://other" + hostnameSuffix},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"user": {Token: "token"},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"main":  {Cluster: "main", AuthInfo: "user"},
+			"other": {Cluster: "other", AuthInfo: "user"},
+		},
+		CurrentContext: "main",
+	}
+
+	tests := []struct {
+		name            string
+		overrides       *clientcmd.ConfigOverrides
+		timeout         time.Duration
+		wantToken       string
+		wantTimeout     time.Duration
+		wantImpersonate string
+	}{
+		{
+			name:        "no overrides",
+			overrides:   nil,
+			timeout:     time.Second,
+			wantToken:   "token",
+			wantTimeout: time.Second,
+		},
+		{
+			name: "context specific overrides",
+			overrides: &clientcmd.ConfigOverrides{
+				CurrentContext: "main",
+				Context:        clientcmdapi.Context{Cluster: "main", AuthInfo: "whomst"},
+				AuthInfo:       clientcmdapi.AuthInfo{Token: "override", ClientCertificate: "cert.pem"},
+				ClusterInfo:    clientcmdapi.Cluster{Server: "https://elsewhere"},
+				Timeout:        "0",
+			},
+			timeout:     time.Second,
+			wantToken:   "token",
+			wantTimeout: time.Second,
+		},
+		{
+			name: "impersonation",
+			overrides: &clientcmd.ConfigOverrides{
+				AuthInfo: clientcmdapi.AuthInfo{Impersonate: "jane", ImpersonateGroups: []string{"admins"}},
+			},
+			timeout:         time.Second,
+			wantToken:       "token",
+			wantTimeout:     time.Second,
+			wantImpersonate: "jane",
+		},
+		{
+			name:        "request timeout",
+			overrides:   &clientcmd.ConfigOverrides{Timeout: "5s"},
+			timeout:     time.Second,
+			wantToken:   "token",
+			wantTimeout: 5 * time.Second,
+		},
+		{
+			name:        "no timeout",
+			overrides:   &clientcmd.ConfigOverrides{},
+			timeout:     0,
+			wantToken:   "token",
+			wantTimeout: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contextConfig := clientcmd.NewNonInteractiveClientConfig(rawConfig, "other", contextOverrides(tt.overrides, tt.timeout), nil)
+			clientConfig, err := contextConfig.ClientConfig()
+			if err != nil {
+				t.Fatalf("ClientConfig() error = %v", err)
+			}
+			if want := "https://other" + hostnameSuffix; clientConfig.Host != want {
+				t.Errorf("contextOverrides() host = %s, want %s", clientConfig.Host, want)
+			}
+			if clientConfig.BearerToken != tt.wantToken {
+				t.Errorf("contextOverrides() token = %s, want %s", clientConfig.BearerToken, tt.wantToken)
+			}
+			if clientConfig.Timeout != tt.wantTimeout {
+				t.Errorf("contextOverrides() timeout = %s, want %s", clientConfig.Timeout, tt.wantTimeout)
+			}
+			if clientConfig.Impersonate.UserName != tt.wantImpersonate {
+				t.Errorf("contextOverrides() impersonate = %s, want %s", clientConfig.Impersonate.UserName, tt.wantImpersonate)
+			}
+		})
+	}
+}
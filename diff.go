@@ -0,0 +1,156 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type DiffRunner func(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, opts RunOptions, from, to string) ([]InventoryChange, error)
+
+// InventoryChangeType is how a DigitalOcean resource changed between two inventories
+type InventoryChangeType string
+
+const (
+	InventoryAdded     InventoryChangeType = "added"
+	InventoryRemoved   InventoryChangeType = "removed"
+	InventoryRepointed InventoryChangeType = "re-pointed"
+)
+
+// InventoryChange is a difference between two inventories. Resources are
+// matched by their type and owner, so a Service whose Load Balancer was
+// recreated is re-pointed from the ID of the old one to the new one.
+type InventoryChange struct {
+	Change InventoryChangeType
+	Type   string
+	// Owner is not set for the cluster itself
+	Owner *InventoryOwner
+	// From is empty for added resources, and To for removed ones
+	From, To *InventoryItem
+}
+
+// ReadInventory decodes an inventory printed by inventory --format json
+func ReadInventory(r io.Reader) (*Inventory, error) {
+	var inventory Inventory
+	if err := json.NewDecoder(r).Decode(&inventory); err != nil {
+		return nil, err
+	}
+	if inventory.APIVersion != RecordListVersion || inventory.Kind != "Inventory" {
+		return nil, fmt.Errorf("expected an inventory of apiVersion %s, got kind %q of apiVersion %q", RecordListVersion, inventory.Kind, inventory.APIVersion)
+	}
+	return &inventory, nil
+}
+
+// inventoryKey identifies an item across inventories by its type and owner
+func inventoryKey(item InventoryItem) string {
+	if item.Owner == nil {
+		return item.Type
+	}
+	return fmt.Sprintf("%s %s %s/%s", item.Type, item.Owner.Kind, item.Owner.Namespace, item.Owner.Name)
+}
+
+// DiffInventories returns the resources added, removed or re-pointed from one
+// inventory to the other, in the order of their items
+func DiffInventories(from, to *Inventory) []InventoryChange {
+	toItems := map[string]*InventoryItem{}
+	for i := range to.Items {
+		toItems[inventoryKey(to.Items[i])] = &to.Items[i]
+	}
+
+	var changes []InventoryChange
+	fromKeys := map[string]bool{}
+	for i := range from.Items {
+		fromItem := &from.Items[i]
+		key := inventoryKey(*fromItem)
+		fromKeys[key] = true
+
+		toItem, ok := toItems[key]
+		switch {
+		case !ok:
+			changes = append(changes, InventoryChange{Change: InventoryRemoved, Type: fromItem.Type, Owner: fromItem.Owner, From: fromItem})
+		case toItem.ID != fromItem.ID:
+			changes = append(changes, InventoryChange{Change: InventoryRepointed, Type: fromItem.Type, Owner: toItem.Owner, From: fromItem, To: toItem})
+		}
+	}
+	for i := range to.Items {
+		toItem := &to.Items[i]
+		if !fromKeys[inventoryKey(*toItem)] {
+			changes = append(changes, InventoryChange{Change: InventoryAdded, Type: toItem.Type, Owner: toItem.Owner, To: toItem})
+		}
+	}
+
+	// changes are sorted like the items they are about, so that additions are not all listed last
+	sort.SliceStable(changes, func(i, j int) bool { return inventoryItemLess(changes[i].item(), changes[j].item()) })
+	return changes
+}
+
+// item returns the item the change is about, as it was before the change when it existed
+func (c InventoryChange) item() InventoryItem {
+	if c.From != nil {
+		return *c.From
+	}
+	return *c.To
+}
+
+// loadInventory reads the inventory file at source or, when there is no such
+// file, fetches the inventory of the context of kubeConfig named source with
+// the context overrides of opts
+func loadInventory(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, opts RunOptions, source string) (*Inventory, error) {
+	if f, err := os.Open(source); err == nil {
+		defer f.Close()
+		inventory, err := ReadInventory(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading inventory %s: %s", source, err)
+		}
+		return inventory, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	rawConfig, err := kubeConfig.RawConfig()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := rawConfig.Contexts[source]; !ok {
+		return nil, fmt.Errorf("%s is neither an inventory file nor a context of the kube config", source)
+	}
+
+	contextConfig := clientcmd.NewNonInteractiveClientConfig(rawConfig, source, contextOverrides(opts.ContextOverrides, 0), nil)
+	inventory, err := FetchInventory(ctx, writer, contextConfig, RunOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("context %s: %s", source, err)
+	}
+	return inventory, nil
+}
+
+// Diff compares two inventories, each given either as the path of a file
+// printed by inventory --format json or as the name of a kube context
+func Diff(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, opts RunOptions, from, to string) ([]InventoryChange, error) {
+	fromInventory, err := loadInventory(ctx, writer, kubeConfig, opts, from)
+	if err != nil {
+		return nil, err
+	}
+	toInventory, err := loadInventory(ctx, writer, kubeConfig, opts, to)
+	if err != nil {
+		return nil, err
+	}
+	return DiffInventories(fromInventory, toInventory), nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestDiffInventories(t *testing.T) {
+	cluster := InventoryItem{Type: "kubernetes_cluster", ID: "cluster-id"}
+	node1 := InventoryItem{Type: "droplet", ID: "1", Owner: &InventoryOwner{Kind: "Node", Name: "node-1"}}
+	node2 := InventoryItem{Type: "droplet", ID: "2", Owner: &InventoryOwner{Kind: "Node", Name: "node-2"}}
+	oldLB := InventoryItem{Type: "load_balancer", ID: "lb-old", Owner: &InventoryOwner{Kind: "Service", Namespace: "web", Name: "web"}}
+	newLB := InventoryItem{Type: "load_balancer", ID: "lb-new", Owner: &InventoryOwner{Kind: "Service", Namespace: "web", Name: "web"}}
+	volume := InventoryItem{Type: "volume", ID: "volume-1", Owner: &InventoryOwner{Kind: "PersistentVolumeClaim", Namespace: "web", Name: "data"}}
+
+	tests := []struct {
+		name string
+		from []InventoryItem
+		to   []InventoryItem
+		want []string
+	}{
+		{
+			name: "unchanged",
+			from: []InventoryItem{cluster, node1, oldLB, volume},
+			to:   []InventoryItem{cluster, node1, oldLB, volume},
+			want: nil,
+		},
+		{
+			name: "node replaced and load balancer recreated",
+			from: []InventoryItem{cluster, node1, oldLB, volume},
+			to:   []InventoryItem{cluster, node2, newLB, volume},
+			want: []string{"removed droplet node-1", "added droplet node-2", "re-pointed load_balancer web lb-old lb-new"},
+		},
+		{
+			name: "another cluster",
+			from: []InventoryItem{cluster},
+			to:   []InventoryItem{{Type: "kubernetes_cluster", ID: "other-id"}},
+			want: []string{"re-pointed kubernetes_cluster cluster-id other-id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := DiffInventories(&Inventory{Items: tt.from}, &Inventory{Items: tt.to})
+
+			var got []string
+			for _, change := range changes {
+				s := string(change.Change) + " " + change.Type
+				if change.Owner != nil {
+					s += " " + change.Owner.Name
+				}
+				if change.Change == InventoryRepointed {
+					s += " " + change.From.ID + " " + change.To.ID
+				}
+				got = append(got, s)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffInventories() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "doweb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"before.json": `{"apiVersion": "doweb.digitalocean.com/v1", "kind": "Inventory", "items": [{"type": "droplet", "id": "1", "owner": {"kind": "Node", "name": "node-1"}}]}`,
+		"after.json":  `{"apiVersion": "doweb.digitalocean.com/v1", "kind": "Inventory", "items": []}`,
+		"links.json":  `{"apiVersion": "doweb.digitalocean.com/v1", "kind": "LinkList", "items": []}`,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	kubeConfig := clientcmd.NewDefaultClientConfig(clientcmdapi.Config{}, &clientcmd.ConfigOverrides{})
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		want    int
+		wantErr string
+	}{
+		{name: "files", from: "before.json", to: "after.json", want: 1},
+		{name: "not an inventory", from: "before.json", to: "links.json", wantErr: `got kind "LinkList"`},
+		{name: "unknown context", from: "before.json", to: "prod", wantErr: "prod is neither an inventory file nor a context of the kube config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := filepath.Join(dir, tt.from), tt.to
+			if _, ok := files[to]; ok {
+				to = filepath.Join(dir, to)
+			}
+
+			got, err := Diff(context.TODO(), ioutil.Discard, kubeConfig, RunOptions{}, from, to)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Diff() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("Diff() = %d changes, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const gatewayAPIGroup = "gateway.networking.k8s.io"
+
+// gatewayAPIVersions are tried in order when reading Gateway API objects
+var gatewayAPIVersions = []string{"v1", "v1beta1"}
+
+// gatewayServiceLabels are the labels Gateway API controllers put on the
+// Services they create for a Gateway. An empty namespace label means the
+// Service lives in the Gateway's namespace.
+var gatewayServiceLabels = []struct {
+	name      string
+	namespace string
+}{
+	// Istio, NGINX Gateway Fabric and others following the upstream convention
+	{name: "gateway.networking.k8s.io/gateway-name"},
+	// Envoy Gateway
+	{name: "gateway.envoyproxy.io/owning-gateway-name", namespace: "gateway.envoyproxy.io/owning-gateway-namespace"},
+	// Cilium
+	{name: "io.cilium.gateway/owning-gateway"},
+}
+
+// Gateway resolves a Gateway API Gateway to the Load Balancer of the Service
+// backing it, found through the Gateway's addresses or the controller's labels.
+func (cp *DOCloudPather) Gateway(ctx context.Context, namespace, name string) (Link, error) {
+	gw, err := cp.getObject(ctx, gatewayAPIGroup, gatewayAPIVersions, "gateways", namespace, name)
+	if err != nil {
+		return Link{}, err
+	}
+
+	lbServices, err := cp.loadBalancerServices(ctx)
+	if err != nil {
+		return Link{}, err
+	}
+
+	addresses := sets.NewString()
+	statusAddresses, _, _ := unstructured.NestedSlice(gw.Object, "status", "addresses")
+	for _, address := range statusAddresses {
+		if address, ok := address.(map[string]interface{}); ok {
+			value, _, _ := unstructured.NestedString(address, "value")
+			addresses.Insert(value)
+		}
+	}
+
+	if svc := serviceByAddress(lbServices, addresses); svc != nil {
+		return cp.Service(ctx, svc.Namespace, svc.Name)
+	}
+
+	var matches []corev1.Service
+	for _, svc := range lbServices {
+		if isGatewayService(svc, namespace, name) {
+			matches = append(matches, svc)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Link{}, fmt.Errorf("no LoadBalancer Service found for Gateway %s by its addresses or its controller's labels", name)
+	case 1:
+		return cp.Service(ctx, matches[0].Namespace, matches[0].Name)
+	default:
+		return Link{}, fmt.Errorf("found %d LoadBalancer Services for Gateway %s", len(matches), name)
+	}
+}
+
+// HTTPRoute resolves an HTTPRoute to the Load Balancers of its parent Gateways.
+func (cp *DOCloudPather) HTTPRoute(ctx context.Context, namespace, name string) ([]Link, error) {
+	route, err := cp.getObject(ctx, gatewayAPIGroup, gatewayAPIVersions, "httproutes", namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+
+	var links []Link
+	var notes []string
+	for _, ref := range parentRefs {
+		ref, ok := ref.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		group, found, _ := unstructured.NestedString(ref, "group")
+		if found && group != gatewayAPIGroup {
+			continue
+		}
+		kind, found, _ := unstructured.NestedString(ref, "kind")
+		if found && kind != "Gateway" {
+			continue
+		}
+
+		gwName, _, _ := unstructured.NestedString(ref, "name")
+		gwNamespace, _, _ := unstructured.NestedString(ref, "namespace")
+		if gwNamespace == "" {
+			gwNamespace = namespace
+		}
+
+		link, err := cp.Gateway(ctx, gwNamespace, gwName)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("skipping Gateway %s (namespace %s): %s", gwName, gwNamespace, err))
+			continue
+		}
+		links = appendLink(links, link)
+	}
+
+	if len(links) == 0 {
+		return nil, fmt.Errorf("HTTPRoute %s has no parent Gateways backed by a DigitalOcean Load Balancer", name)
+	}
+
+	links[0].Notes = append(links[0].Notes, notes...)
+	return links, nil
+}
+
+func isGatewayService(svc corev1.Service, namespace, name string) bool {
+	for _, labels := range gatewayServiceLabels {
+		if svc.Labels[labels.name] != name {
+			continue
+		}
+
+		if labels.namespace == "" {
+			if svc.Namespace == namespace {
+				return true
+			}
+		} else if svc.Labels[labels.namespace] == namespace {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newGatewayAPIObject(kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	obj.SetAPIVersion(gatewayAPIGroup + "/v1")
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+// createGatewayAPIObject creates obj through the dynamic client, as the fake
+// client cannot guess the resource name of Gateways from their kind
+func createGatewayAPIObject(ctx context.Context, cp *DOCloudPather, resource string, obj *unstructured.Unstructured) {
+	gvr := schema.GroupVersionResource{Group: gatewayAPIGroup, Version: "v1", Resource: resource}
+	cp.dynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+}
+
+func newGatewayService(name, namespace, id, ip string, labels map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: map[string]string{lbaasAnnotation: id},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: ip}},
+			},
+		},
+	}
+}
+
+func TestDOCloudPather_Gateway(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+	gwName := "gw-1"
+
+	tests := []struct {
+		name     string
+		gateway  *unstructured.Unstructured
+		services []*corev1.Service
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:    "inexistent gateway",
+			gateway: nil,
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "gateway matched by address",
+			gateway: newGatewayAPIObject("Gateway", namespace, gwName, map[string]interface{}{
+				"status": map[string]interface{}{
+					"addresses": []interface{}{
+						map[string]interface{}{"type": "IPAddress", "value": "203.0.113.10"},
+					},
+				},
+			}),
+			services: []*corev1.Service{
+				newGatewayService("other", "default", "other-id", "203.0.113.20", nil),
+				newGatewayService("gw-svc", "gateway-system", "gw-id", "203.0.113.10", nil),
+			},
+			want:    "networking/load_balancers/gw-id",
+			wantErr: false,
+		},
+		{
+			name:    "gateway matched by upstream labels",
+			gateway: newGatewayAPIObject("Gateway", namespace, gwName, map[string]interface{}{}),
+			services: []*corev1.Service{
+				newGatewayService("gw-svc", namespace, "gw-id", "", map[string]string{
+					"gateway.networking.k8s.io/gateway-name": gwName,
+				}),
+			},
+			want:    "networking/load_balancers/gw-id",
+			wantErr: false,
+		},
+		{
+			name:    "gateway matched by envoy gateway labels",
+			gateway: newGatewayAPIObject("Gateway", namespace, gwName, map[string]interface{}{}),
+			services: []*corev1.Service{
+				newGatewayService("envoy-ns-gw-1", "envoy-gateway-system", "gw-id", "", map[string]string{
+					"gateway.envoyproxy.io/owning-gateway-name":      gwName,
+					"gateway.envoyproxy.io/owning-gateway-namespace": namespace,
+				}),
+			},
+			want:    "networking/load_balancers/gw-id",
+			wantErr: false,
+		},
+		{
+			name:    "gateway without a service",
+			gateway: newGatewayAPIObject("Gateway", namespace, gwName, map[string]interface{}{}),
+			services: []*corev1.Service{
+				newGatewayService("gw-svc", "other-ns", "gw-id", "", map[string]string{
+					"gateway.networking.k8s.io/gateway-name": gwName,
+				}),
+			},
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := newFakeDOCloudPather()
+			if tt.gateway != nil {
+				createGatewayAPIObject(ctx, cp, "gateways", tt.gateway)
+			}
+			for _, svc := range tt.services {
+				cp.clientset.CoreV1().Services(svc.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+			}
+
+			got, err := cp.Gateway(ctx, namespace, gwName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DOCloudPather.Gateway() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.path() != tt.want {
+				t.Errorf("DOCloudPather.Gateway() = %v, want %v", got.path(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDOCloudPather_HTTPRoute(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+	routeName := "route-1"
+
+	gateways := []*unstructured.Unstructured{
+		newGatewayAPIObject("Gateway", namespace, "gw-1", map[string]interface{}{}),
+		newGatewayAPIObject("Gateway", "shared", "gw-2", map[string]interface{}{}),
+	}
+	services := []*corev1.Service{
+		newGatewayService("gw-1", namespace, "id-1", "", map[string]string{
+			"gateway.networking.k8s.io/gateway-name": "gw-1",
+		}),
+		newGatewayService("gw-2", "shared", "id-2", "", map[string]string{
+			"gateway.networking.k8s.io/gateway-name": "gw-2",
+		}),
+	}
+
+	tests := []struct {
+		name       string
+		parentRefs []interface{}
+		want       []string
+		wantNotes  []string
+		wantErr    bool
+	}{
+		{
+			name:       "route without parents",
+			parentRefs: []interface{}{},
+			want:       nil,
+			wantErr:    true,
+		},
+		{
+			name: "route with multiple parents",
+			parentRefs: []interface{}{
+				map[string]interface{}{"name": "gw-1"},
+				map[string]interface{}{"name": "gw-1", "sectionName": "https"},
+				map[string]interface{}{"name": "gw-2", "namespace": "shared", "group": gatewayAPIGroup, "kind": "Gateway"},
+				map[string]interface{}{"name": "mesh", "group": "", "kind": "Service"},
+				map[string]interface{}{"name": "gw-3"},
+			},
+			want:      []string{"networking/load_balancers/id-1", "networking/load_balancers/id-2"},
+			wantNotes: []string{fmt.Sprintf("skipping Gateway gw-3 (namespace %s): gateways.%s \"gw-3\" not found", namespace, gatewayAPIGroup)},
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := newGatewayAPIObject("HTTPRoute", namespace, routeName, map[string]interface{}{
+				"spec": map[string]interface{}{"parentRefs": tt.parentRefs},
+			})
+
+			cp := newFakeDOCloudPather()
+			createGatewayAPIObject(ctx, cp, "httproutes", route)
+			for _, gw := range gateways {
+				createGatewayAPIObject(ctx, cp, "gateways", gw)
+			}
+			for _, svc := range services {
+				cp.clientset.CoreV1().Services(svc.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+			}
+
+			got, err := cp.HTTPRoute(ctx, namespace, routeName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DOCloudPather.HTTPRoute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(paths(got), tt.want) {
+				t.Errorf("DOCloudPather.HTTPRoute() = %v, want %v", paths(got), tt.want)
+			}
+
+			var gotNotes []string
+			if len(got) > 0 {
+				gotNotes = got[0].Notes
+			}
+			if !reflect.DeepEqual(gotNotes, tt.wantNotes) {
+				t.Errorf("DOCloudPather.HTTPRoute() notes = %v, want %v", gotNotes, tt.wantNotes)
+			}
+		})
+	}
+}
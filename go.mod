@@ -3,7 +3,10 @@ module github.com/do-community/kubectldoweb
 go 1.14
 
 require (
+	github.com/atotto/clipboard v0.1.2
+	github.com/gdamore/tcell v1.3.0
 	github.com/imdario/mergo v0.3.9 // indirect
+	github.com/rivo/tview v0.0.0-20200915114512-42866ecf6ca6
 	github.com/skratchdot/open-golang v0.0.0-20200116055534-eef842397966
 	github.com/urfave/cli/v2 v2.2.0
 	golang.org/x/net v0.7.0
@@ -13,4 +16,5 @@ require (
 	k8s.io/apimachinery v0.18.2
 	k8s.io/client-go v0.0.0-20200426040145-5159cff060fb
 	k8s.io/utils v0.0.0-20200414100711-2df71ebbae66 // indirect
+	sigs.k8s.io/yaml v1.2.0
 )
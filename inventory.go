@@ -0,0 +1,270 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type InventoryRunner func(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, opts RunOptions) (*Inventory, error)
+
+// Inventory is the document listing the DigitalOcean resources backing a
+// cluster. Its items are sorted so that it can be committed and diffed.
+type Inventory struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	ClusterID  string          `json:"clusterID,omitempty"`
+	Items      []InventoryItem `json:"items"`
+}
+
+// InventoryItem is a DigitalOcean resource and the Kubernetes object it is charged to
+type InventoryItem struct {
+	// Type is the DigitalOcean resource type: kubernetes_cluster, droplet,
+	// load_balancer or volume
+	Type string `json:"type"`
+	// ID is empty when the resource could not be identified, e.g. a volume without a CSI handle
+	ID     string `json:"id,omitempty"`
+	URL    string `json:"url"`
+	Region string `json:"region,omitempty"`
+	// Owner is not set for the cluster itself
+	Owner *InventoryOwner `json:"owner,omitempty"`
+}
+
+// InventoryOwner is the Kubernetes object a DigitalOcean resource is provisioned for:
+// a node, a Service, or the claim of a volume, or its PersistentVolume when it is not claimed
+type InventoryOwner struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Workloads are the controllers of the pods selected by a Service or mounting a claim, e.g. deployment/web
+	Workloads []string          `json:"workloads,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// workloadIndex finds the workloads of the pods selected by Services or mounting claims
+type workloadIndex struct {
+	pods []corev1.Pod
+	// selectors are the pod selectors of Services by namespace/name
+	selectors map[string]labels.Selector
+	// deployments are the Deployments of ReplicaSets by namespace/name
+	deployments map[string]string
+}
+
+func (cp *DOCloudPather) workloadIndex(ctx context.Context) (*workloadIndex, error) {
+	index := &workloadIndex{
+		selectors:   map[string]labels.Selector{},
+		deployments: map[string]string{},
+	}
+
+	pods, err := cp.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	index.pods = pods.Items
+
+	services, err := cp.clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range services.Items {
+		// a Service without a selector does not select any pod
+		if len(svc.Spec.Selector) > 0 {
+			index.selectors[svc.Namespace+"/"+svc.Name] = labels.SelectorFromSet(svc.Spec.Selector)
+		}
+	}
+
+	replicaSets, err := cp.clientset.AppsV1().ReplicaSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i, rs := range replicaSets.Items {
+		if owner := metav1.GetControllerOf(&replicaSets.Items[i]); owner != nil && owner.Kind == kindDeployment {
+			index.deployments[rs.Namespace+"/"+rs.Name] = owner.Name
+		}
+	}
+
+	return index, nil
+}
+
+// workloadOf returns the controller of a pod, going up from ReplicaSets to
+// their Deployment, or the pod itself when it has none
+func (index *workloadIndex) workloadOf(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "pod/" + pod.Name
+	}
+	if deployment, ok := index.deployments[pod.Namespace+"/"+owner.Name]; ok && owner.Kind == kindReplicaSet {
+		return "deployment/" + deployment
+	}
+	return strings.ToLower(owner.Kind) + "/" + owner.Name
+}
+
+// workloads returns the sorted workloads of the pods of namespace matching
+func (index *workloadIndex) workloads(namespace string, matching func(*corev1.Pod) bool) []string {
+	seen := map[string]bool{}
+	var workloads []string
+	for i := range index.pods {
+		pod := &index.pods[i]
+		if pod.Namespace != namespace || !matching(pod) {
+			continue
+		}
+		if workload := index.workloadOf(pod); !seen[workload] {
+			seen[workload] = true
+			workloads = append(workloads, workload)
+		}
+	}
+	sort.Strings(workloads)
+	return workloads
+}
+
+func (index *workloadIndex) serviceWorkloads(namespace, name string) []string {
+	selector, ok := index.selectors[namespace+"/"+name]
+	if !ok {
+		return nil
+	}
+	return index.workloads(namespace, func(pod *corev1.Pod) bool { return selector.Matches(labels.Set(pod.Labels)) })
+}
+
+func (index *workloadIndex) claimWorkloads(namespace, name string) []string {
+	return index.workloads(namespace, func(pod *corev1.Pod) bool { return usesClaim(*pod, name) })
+}
+
+// newInventory lists the resources of overview, charging volumes to their claim
+func newInventory(overview *Overview, index *workloadIndex) *Inventory {
+	inventory := &Inventory{
+		APIVersion: RecordListVersion,
+		Kind:       "Inventory",
+		ClusterID:  overview.Cluster.ID,
+		Items:      []InventoryItem{},
+	}
+	if overview.Cluster.ID != "" {
+		inventory.Items = append(inventory.Items, inventoryItem(overview.Cluster, nil))
+	}
+
+	for _, entry := range overview.Nodes {
+		inventory.Items = append(inventory.Items, inventoryItem(entry.Links[0], inventoryOwner(entry, nil)))
+	}
+
+	for _, entry := range overview.LoadBalancers {
+		// Load Balancers still being provisioned have no ID yet
+		if entry.Err != nil {
+			continue
+		}
+		workloads := index.serviceWorkloads(entry.Namespace, entry.Name)
+		inventory.Items = append(inventory.Items, inventoryItem(entry.Links[0], inventoryOwner(entry, workloads)))
+	}
+
+	claims := map[string]OverviewEntry{}
+	for _, claim := range overview.Claims {
+		claims[claim.Namespace+"/"+claim.Name] = claim
+	}
+	for _, entry := range overview.Volumes {
+		owner := inventoryOwner(entry, nil)
+		if claim, ok := claims[entry.Claim]; ok {
+			owner = inventoryOwner(claim, index.claimWorkloads(claim.Namespace, claim.Name))
+		}
+		inventory.Items = append(inventory.Items, inventoryItem(entry.Links[0], owner))
+	}
+
+	sortInventoryItems(inventory.Items)
+	return inventory
+}
+
+func inventoryItem(link Link, owner *InventoryOwner) InventoryItem {
+	return InventoryItem{
+		Type:   string(link.Kind),
+		ID:     link.ID,
+		URL:    link.URL,
+		Region: link.Region,
+		Owner:  owner,
+	}
+}
+
+func inventoryOwner(entry OverviewEntry, workloads []string) *InventoryOwner {
+	return &InventoryOwner{
+		Kind:      entry.Kind,
+		Namespace: entry.Namespace,
+		Name:      entry.Name,
+		Workloads: workloads,
+		Labels:    entry.Labels,
+	}
+}
+
+// inventoryTypes orders the items of an inventory
+var inventoryTypes = []ResourceKind{ResourceKubernetesCluster, ResourceDroplet, ResourceLoadBalancer, ResourceVolume}
+
+// sortInventoryItems sorts items by type, then by owner and ID
+func sortInventoryItems(items []InventoryItem) {
+	sort.SliceStable(items, func(i, j int) bool { return inventoryItemLess(items[i], items[j]) })
+}
+
+func inventoryItemLess(a, b InventoryItem) bool {
+	rank := func(item InventoryItem) int {
+		for i, kind := range inventoryTypes {
+			if string(kind) == item.Type {
+				return i
+			}
+		}
+		return len(inventoryTypes)
+	}
+	key := func(item InventoryItem) string {
+		if item.Owner == nil {
+			return item.ID
+		}
+		return item.Owner.Namespace + "/" + item.Owner.Name + "/" + item.ID
+	}
+
+	if rank(a) != rank(b) {
+		return rank(a) < rank(b)
+	}
+	return key(a) < key(b)
+}
+
+// Inventory lists the DigitalOcean resources backing the cluster and the
+// Kubernetes objects they are charged to
+func (c *Client) Inventory(ctx context.Context) (*Inventory, error) {
+	overview, err := c.Overview(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := c.cp.workloadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newInventory(overview, index), nil
+}
+
+// FetchInventory lists the DigitalOcean resources backing the cluster
+// kubeConfig points at, or the objects of opts.Filenames
+func FetchInventory(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, opts RunOptions) (*Inventory, error) {
+	if opts.AllContexts {
+		return nil, fmt.Errorf("an inventory covers a single context")
+	}
+
+	client, _, err := clientFor(writer, kubeConfig, "", opts)
+	if err != nil {
+		return nil, err
+	}
+	return client.Inventory(ctx)
+}
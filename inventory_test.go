@@ -0,0 +1,113 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+)
+
+func TestClient_Inventory(t *testing.T) {
+	ctx := context.TODO()
+	cp := newFakeDOCloudPather()
+	cp.clientConfig = &restclient.Config{Host: "https://cluster-id" + hostnameSuffix}
+	controller := true
+
+	cp.clientset.CoreV1().Nodes().Create(ctx, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{nodePoolNameLabel: "web"}},
+		Spec:       corev1.NodeSpec{ProviderID: nodeIDPrefix + "123"},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Services("web").Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "web", Labels: map[string]string{"team": "payments"}, Annotations: map[string]string{lbaasAnnotation: "lb-id"}},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, Selector: map[string]string{"app": "web"}},
+	}, metav1.CreateOptions{})
+	cp.clientset.AppsV1().ReplicaSets("web").Create(ctx, &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-5d4f", Namespace: "web", OwnerReferences: []metav1.OwnerReference{{Kind: kindDeployment, Name: "web", Controller: &controller}}},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Pods("web").Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-5d4f-x", Namespace: "web", Labels: map[string]string{"app": "web"}, OwnerReferences: []metav1.OwnerReference{{Kind: kindReplicaSet, Name: "web-5d4f", Controller: &controller}}},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Pods("web").Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "web", OwnerReferences: []metav1.OwnerReference{{Kind: kindStatefulSet, Name: "db", Controller: &controller}}},
+		Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+			Name:         "data",
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-db-0"}},
+		}}},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumes().Create(ctx, &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: csiDriverName, VolumeHandle: "volume-1"},
+			},
+			ClaimRef: &corev1.ObjectReference{Namespace: "web", Name: "data-db-0"},
+		},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumeClaims("web").Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "web", Labels: map[string]string{"app": "db"}},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumes().Create(ctx, &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-released"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: csiDriverName, VolumeHandle: "volume-0"},
+			},
+		},
+	}, metav1.CreateOptions{})
+	client := newClient(cp, nil, ClientOptions{})
+
+	got, err := client.Inventory(ctx)
+	if err != nil {
+		t.Fatalf("Client.Inventory() error = %v", err)
+	}
+
+	want := []InventoryItem{
+		{Type: "kubernetes_cluster", ID: "cluster-id", URL: DefaultCloudBase + "kubernetes/clusters/cluster-id"},
+		{
+			Type: "droplet", ID: "123", URL: DefaultCloudBase + "droplets/123",
+			Owner: &InventoryOwner{Kind: "Node", Name: "node-1", Labels: map[string]string{nodePoolNameLabel: "web"}},
+		},
+		{
+			Type: "load_balancer", ID: "lb-id", URL: DefaultCloudBase + "networking/load_balancers/lb-id",
+			Owner: &InventoryOwner{Kind: "Service", Namespace: "web", Name: "web", Workloads: []string{"deployment/web"}, Labels: map[string]string{"team": "payments"}},
+		},
+		{
+			Type: "volume", ID: "volume-0", URL: DefaultCloudBase + "volumes/volume-0",
+			Owner: &InventoryOwner{Kind: "PersistentVolume", Name: "pv-released"},
+		},
+		{
+			Type: "volume", ID: "volume-1", URL: DefaultCloudBase + "volumes/volume-1",
+			Owner: &InventoryOwner{Kind: "PersistentVolumeClaim", Namespace: "web", Name: "data-db-0", Workloads: []string{"statefulset/db"}, Labels: map[string]string{"app": "db"}},
+		},
+	}
+	if got.ClusterID != "cluster-id" {
+		t.Errorf("Client.Inventory() cluster ID = %s, want cluster-id", got.ClusterID)
+	}
+	if len(got.Items) != len(want) {
+		t.Fatalf("Client.Inventory() = %d items, want %d", len(got.Items), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got.Items[i], want[i]) {
+			t.Errorf("Client.Inventory() item %d = %+v (owner %+v), want %+v (owner %+v)", i, got.Items[i], got.Items[i].Owner, want[i], want[i].Owner)
+		}
+	}
+}
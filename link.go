@@ -0,0 +1,106 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ResourceKind is the type of a DigitalOcean resource
+type ResourceKind string
+
+const (
+	ResourceKubernetesCluster ResourceKind = "kubernetes_cluster"
+	ResourceNodePool          ResourceKind = "kubernetes_node_pool"
+	ResourceDroplet           ResourceKind = "droplet"
+	ResourceLoadBalancer      ResourceKind = "load_balancer"
+	ResourceVolume            ResourceKind = "volume"
+)
+
+// regionLabels hold the region of a node, from the most to the least recent
+var regionLabels = []string{corev1.LabelZoneRegionStable, corev1.LabelZoneRegion}
+
+// csiRegionKey is the topology key the DO CSI driver uses in volume node affinities
+const csiRegionKey = "region"
+
+// Link points at the control panel page of a DigitalOcean resource
+type Link struct {
+	Kind ResourceKind
+	// ID is empty when the resource could only be resolved to a list page
+	ID     string
+	Title  string
+	URL    string
+	Region string
+	// Notes explain how the link was resolved, e.g. why a fallback page is used
+	Notes []string
+}
+
+func newLink(kind ResourceKind, id, title, path string) Link {
+	return Link{
+		Kind:  kind,
+		ID:    id,
+		Title: title,
+		URL:   DefaultCloudBase + path,
+	}
+}
+
+// path returns the link's URL relative to the control panel
+func (l Link) path() string {
+	return strings.TrimPrefix(l.URL, DefaultCloudBase)
+}
+
+// rebased returns the link with its URL moved from DefaultCloudBase to base
+func (l Link) rebased(base string) Link {
+	l.URL = base + l.path()
+	return l
+}
+
+// appendLink adds link to links unless a link to the same URL is already present
+func appendLink(links []Link, link Link) []Link {
+	for _, existing := range links {
+		if existing.URL == link.URL {
+			return links
+		}
+	}
+	return append(links, link)
+}
+
+func nodeRegion(node *corev1.Node) string {
+	for _, label := range regionLabels {
+		if region, ok := node.Labels[label]; ok {
+			return region
+		}
+	}
+	return ""
+}
+
+func volumeRegion(pv *corev1.PersistentVolume) string {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return ""
+	}
+
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Operator != corev1.NodeSelectorOpIn || len(expr.Values) != 1 {
+				continue
+			}
+			if expr.Key == csiRegionKey || expr.Key == corev1.LabelZoneRegionStable || expr.Key == corev1.LabelZoneRegion {
+				return expr.Values[0]
+			}
+		}
+	}
+	return ""
+}
@@ -16,93 +16,190 @@ package kubectldoweb
 import (
 	"fmt"
 	"io"
+	"os"
+	"time"
 
 	"golang.org/x/net/context"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-type Runner func(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, namespace, typ, name string) (string, error)
+type Runner func(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, namespace string, refs []Reference, opts RunOptions) ([]Result, error)
+
+// Options tweak which page a resource resolves to
+type Options struct {
+	// Pool resolves nodes to their DOKS node pool instead of their Droplet
+	Pool bool
+	// Page selects a tab of the resolved page, such as insights for a cluster
+	Page string
+	// LabelSelector and FieldSelector select the objects of a type to resolve
+	LabelSelector string
+	FieldSelector string
+	// AllNamespaces resolves the objects of a type in every namespace
+	AllNamespaces bool
+}
+
+// RunOptions are the settings of the entry points of the command line, Run,
+// Which, the Fetch and Watch functions and Diff: where objects are read from
+// on top of how they are resolved
+type RunOptions struct {
+	Options
+	// Filenames are manifests, directories of manifests or - for stdin to
+	// resolve objects from instead of the cluster
+	Filenames []string
+	// ClusterID is the DOKS cluster of the objects in Filenames. Defaults to
+	// the cluster of the kube config.
+	ClusterID string
+	// AllContexts searches the cluster of every context of the kube config
+	// concurrently, giving each ContextTimeout
+	AllContexts    bool
+	ContextTimeout time.Duration
+	// ContextOverrides are the kube config overrides of the command line,
+	// applied to each context searched with AllContexts or compared by Diff
+	ContextOverrides *clientcmd.ConfigOverrides
+}
+
+// Bulk reports whether a type is resolved to all its matching objects rather than to named ones
+func (o Options) Bulk() bool {
+	return o.LabelSelector != "" || o.FieldSelector != "" || o.AllNamespaces
+}
 
-const cloudBase = "https://cloud.digitalocean.com/"
+// DefaultCloudBase is the control panel URL links are built on
+const DefaultCloudBase = "https://cloud.digitalocean.com/"
 
 var ErrMissingArgument = fmt.Errorf("missing argument")
 
-func Run(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, namespace, typ, name string) (string, error) {
+// Run resolves each reference, returning a Result per reference so that one
+// failure does not hide the others. When resolving offline without any
+// reference, every object of opts.Filenames with a DigitalOcean mapping is resolved.
+func Run(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, namespace string, refs []Reference, opts RunOptions) ([]Result, error) {
+	if opts.AllContexts {
+		results, skipped, err := ResolveAllContexts(ctx, kubeConfig, namespace, refs, opts)
+		printSkippedContexts(writer, skipped)
+		return results, err
+	}
+
+	client, objects, err := clientFor(writer, kubeConfig, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Filenames) > 0 && len(refs) == 0 {
+		refs = ObjectReferences(objects)
+	}
+
+	results := make([]Result, 0, len(refs))
+	for _, ref := range refs {
+		results = append(results, client.Resolve(ctx, ref)...)
+	}
+
+	return results, nil
+}
+
+func printSkippedContexts(writer io.Writer, skipped []SkippedContext) {
+	for _, s := range skipped {
+		fmt.Fprintf(writer, "skipping context %s: %s\n", s.Context, s.Err)
+	}
+}
+
+// clientFor returns a Client for the cluster of kubeConfig or, when
+// opts.Filenames are set, an offline Client along with the objects it was loaded with
+func clientFor(writer io.Writer, kubeConfig clientcmd.ClientConfig, namespace string, opts RunOptions) (*Client, []*unstructured.Unstructured, error) {
+	if len(opts.Filenames) > 0 {
+		return offlineClientFor(writer, kubeConfig, namespace, opts)
+	}
+
 	// if a namespace is not explicitly provided, use the default set in kube config
 	if namespace == "" {
 		namespace, _, _ = kubeConfig.Namespace()
 	}
 	if namespace == "" {
 		fmt.Println("could not determine namespace using the provided kube config")
-		return "", ErrMissingArgument
+		return nil, nil, ErrMissingArgument
 	}
 
 	clientConfig, err := kubeConfig.ClientConfig()
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
 	clientset, err := kubernetes.NewForConfig(clientConfig)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
-	cp := &DOCloudPather{
-		clientConfig: clientConfig,
-		clientset:    clientset,
-		output:       writer,
+	client, err := NewClient(clientset, clientConfig, ClientOptions{
+		Namespace: namespace,
+		Output:    writer,
+		Options:   opts.Options,
+	})
+	return client, nil, err
+}
+
+func offlineClientFor(writer io.Writer, kubeConfig clientcmd.ClientConfig, namespace string, opts RunOptions) (*Client, []*unstructured.Unstructured, error) {
+	// the kube config is optional as the cluster may not be reachable, or even configured
+	if namespace == "" {
+		namespace, _, _ = kubeConfig.Namespace()
+	}
+	if opts.ClusterID == "" {
+		if clientConfig, err := kubeConfig.ClientConfig(); err == nil {
+			opts.ClusterID, _ = (&DOCloudPather{clientConfig: clientConfig}).clusterID()
+		}
 	}
 
-	fmt.Fprintf(writer, "opening %s %s (namespace %s)\n", typ, name, namespace)
-	path, err := cloudPatherByType(ctx, cp, typ, namespace, name)
+	objects, err := readObjects(opts.Filenames, os.Stdin)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
-	return path, nil
+	client, err := NewOfflineClient(objects, opts.ClusterID, ClientOptions{
+		Namespace: namespace,
+		Output:    writer,
+		Options:   opts.Options,
+	})
+	return client, objects, err
 }
 
-func cloudPatherByType(ctx context.Context, cp CloudPather, typ, namespace, name string) (string, error) {
-	// cluster is the only type that doesn't take a name
-	if typ == "cluster" {
-		return cp.Cluster(ctx)
+func resolve(ctx context.Context, writer io.Writer, cp CloudPather, mapper meta.RESTMapper, namespace string, ref Reference, opts Options) Result {
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
 	}
 
-	if name == "" {
-		return "", ErrMissingArgument
+	fmt.Fprintf(writer, "opening %s %s (namespace %s)\n", ref.Type, ref.Name, namespace)
+	links, err := cloudPatherByType(ctx, cp, mapper, ref.Type, namespace, ref.Name, opts)
+	if err == nil {
+		links, err = withPage(links, opts.Page)
 	}
 
-	switch typ {
-	case "nodes":
-		fallthrough
-	case "node":
-		fallthrough
-	case "no":
-		return cp.Node(ctx, name)
-
-	case "services":
-		fallthrough
-	case "service":
-		fallthrough
-	case "svc":
-		return cp.Service(ctx, namespace, name)
+	result := Result{Reference: ref, Links: links, Err: err}
+	if resolver, _ := resolvers.resolverFor(ref.Type, mapper); resolver != nil {
+		result.Kind = resolver.GVK.Kind
+		// results carry the namespace the object was looked up in, wherever it came from
+		if resolver.Scope == ScopeNamespaced {
+			result.Reference.Namespace = namespace
+		}
+	}
+	return result
+}
 
-	case "persistentvolume":
-		fallthrough
-	case "persistentvolumes":
-		fallthrough
-	case "pv":
-		return cp.PersistentVolume(ctx, name)
+func cloudPatherByType(ctx context.Context, cp CloudPather, mapper meta.RESTMapper, typ, namespace, name string, opts Options) ([]Link, error) {
+	resolver, err := resolvers.resolverFor(typ, mapper)
+	if err != nil {
+		return nil, err
+	}
 
-	case "persistentvolumeclaim":
-		fallthrough
-	case "persistentvolumeclaims":
-		fallthrough
-	case "pvc":
-		return cp.PersistentVolumeClaim(ctx, namespace, name)
+	if name == "" && (resolver == nil || !resolver.NameOptional) {
+		return nil, ErrMissingArgument
+	}
+	if resolver == nil {
+		return nil, fmt.Errorf("unknown type %s", typ)
+	}
 
-	default:
-		return "", fmt.Errorf("unknown type %s", typ)
+	if resolver.Scope == ScopeCluster {
+		namespace = ""
 	}
+
+	return resolver.Resolve(ctx, cp, namespace, name, opts)
 }
@@ -14,33 +14,79 @@ limitations under the License.
 package kubectldoweb
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"golang.org/x/net/context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 type NoopCloudPather struct{}
 
 var _ CloudPather = &NoopCloudPather{}
 
-func (_ *NoopCloudPather) Cluster(ctx context.Context) (string, error) {
-	return "cluster", nil
+func (_ *NoopCloudPather) Cluster(ctx context.Context) (Link, error) {
+	return Link{Title: "cluster"}, nil
 }
 
-func (_ *NoopCloudPather) Node(ctx context.Context, name string) (string, error) {
-	return "no", nil
+func (_ *NoopCloudPather) Node(ctx context.Context, name string) (Link, error) {
+	return Link{Title: "no"}, nil
 }
 
-func (_ *NoopCloudPather) Service(ctx context.Context, namespace, name string) (string, error) {
-	return "svc", nil
+func (_ *NoopCloudPather) NodePool(ctx context.Context, name string) ([]Link, error) {
+	return []Link{{Title: "np"}}, nil
 }
 
-func (_ *NoopCloudPather) PersistentVolume(ctx context.Context, name string) (string, error) {
-	return "pv", nil
+func (_ *NoopCloudPather) PoolOfNode(ctx context.Context, name string) (Link, error) {
+	return Link{Title: "pool"}, nil
 }
 
-func (_ *NoopCloudPather) PersistentVolumeClaim(ctx context.Context, namespace, name string) (string, error) {
-	return "pvc", nil
+func (_ *NoopCloudPather) Service(ctx context.Context, namespace, name string) (Link, error) {
+	return Link{Title: "svc"}, nil
+}
+
+func (_ *NoopCloudPather) PersistentVolume(ctx context.Context, name string) (Link, error) {
+	return Link{Title: "pv"}, nil
+}
+
+func (_ *NoopCloudPather) PersistentVolumeClaim(ctx context.Context, namespace, name string) (Link, error) {
+	return Link{Title: "pvc"}, nil
+}
+
+func (_ *NoopCloudPather) Pod(ctx context.Context, namespace, name string) ([]Link, error) {
+	return []Link{{Title: "po"}}, nil
+}
+
+func (_ *NoopCloudPather) Workload(ctx context.Context, kind, namespace, name string) ([]Link, error) {
+	return []Link{{Title: kind}, {Title: "volumes"}}, nil
+}
+
+func (_ *NoopCloudPather) Ingress(ctx context.Context, namespace, name string) (Link, error) {
+	return Link{Title: "ing"}, nil
+}
+
+func (_ *NoopCloudPather) Gateway(ctx context.Context, namespace, name string) (Link, error) {
+	return Link{Title: "gtw"}, nil
+}
+
+func (_ *NoopCloudPather) Clients() Clients {
+	return Clients{}
+}
+
+func (_ *NoopCloudPather) HTTPRoute(ctx context.Context, namespace, name string) ([]Link, error) {
+	return []Link{{Title: "httproute"}}, nil
+}
+
+// titles reduces links to their titles, which is all NoopCloudPather sets
+func titles(links []Link) []string {
+	var titles []string
+	for _, link := range links {
+		titles = append(titles, link.Title)
+	}
+	return titles
 }
 
 func Test_cloudPatherWithType(t *testing.T) {
@@ -48,157 +94,465 @@ func Test_cloudPatherWithType(t *testing.T) {
 	tests := []struct {
 		typ     string
 		name    string
-		want    string
+		opts    Options
+		want    []string
 		wantErr bool
 	}{
 		{
 			typ:     "cluster",
 			name:    "",
-			want:    "cluster",
+			want:    []string{"cluster"},
 			wantErr: false,
 		},
 		{
 			typ:     "cluster",
 			name:    "name",
-			want:    "cluster",
+			want:    []string{"cluster"},
 			wantErr: false,
 		},
 
 		{
 			typ:     "no",
 			name:    "",
-			want:    "",
+			want:    nil,
 			wantErr: true,
 		},
 		{
 			typ:     "no",
 			name:    "name",
-			want:    "no",
+			want:    []string{"no"},
 			wantErr: false,
 		},
 		{
 			typ:     "node",
 			name:    "name",
-			want:    "no",
+			want:    []string{"no"},
 			wantErr: false,
 		},
 		{
 			typ:     "nodes",
 			name:    "name",
-			want:    "no",
+			want:    []string{"no"},
+			wantErr: false,
+		},
+
+		{
+			typ:     "no",
+			name:    "name",
+			opts:    Options{Pool: true},
+			want:    []string{"pool"},
+			wantErr: false,
+		},
+
+		{
+			typ:     "np",
+			name:    "",
+			want:    []string{"np"},
+			wantErr: false,
+		},
+		{
+			typ:     "np",
+			name:    "name",
+			want:    []string{"np"},
+			wantErr: false,
+		},
+		{
+			typ:     "nodepool",
+			name:    "name",
+			want:    []string{"np"},
+			wantErr: false,
+		},
+		{
+			typ:     "nodepools",
+			name:    "name",
+			want:    []string{"np"},
 			wantErr: false,
 		},
 
 		{
 			typ:     "svc",
 			name:    "",
-			want:    "",
+			want:    nil,
 			wantErr: true,
 		},
 		{
 			typ:     "svc",
 			name:    "name",
-			want:    "svc",
+			want:    []string{"svc"},
 			wantErr: false,
 		},
 		{
 			typ:     "services",
 			name:    "name",
-			want:    "svc",
+			want:    []string{"svc"},
 			wantErr: false,
 		},
 		{
 			typ:     "service",
 			name:    "name",
-			want:    "svc",
+			want:    []string{"svc"},
 			wantErr: false,
 		},
 
 		{
 			typ:     "pv",
 			name:    "",
-			want:    "",
+			want:    nil,
 			wantErr: true,
 		},
 		{
 			typ:     "pv",
 			name:    "name",
-			want:    "pv",
+			want:    []string{"pv"},
 			wantErr: false,
 		},
 		{
 			typ:     "persistentvolume",
 			name:    "name",
-			want:    "pv",
+			want:    []string{"pv"},
 			wantErr: false,
 		},
 		{
 			typ:     "persistentvolumes",
 			name:    "name",
-			want:    "pv",
+			want:    []string{"pv"},
 			wantErr: false,
 		},
 
 		{
 			typ:     "pvc",
 			name:    "",
-			want:    "",
+			want:    nil,
 			wantErr: true,
 		},
 		{
 			typ:     "pvc",
 			name:    "name",
-			want:    "pvc",
+			want:    []string{"pvc"},
 			wantErr: false,
 		},
 		{
 			typ:     "persistentvolumeclaim",
 			name:    "name",
-			want:    "pvc",
+			want:    []string{"pvc"},
 			wantErr: false,
 		},
 		{
 			typ:     "persistentvolumeclaims",
 			name:    "name",
-			want:    "pvc",
+			want:    []string{"pvc"},
+			wantErr: false,
+		},
+
+		{
+			typ:     "po",
+			name:    "",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			typ:     "po",
+			name:    "name",
+			want:    []string{"po"},
+			wantErr: false,
+		},
+		{
+			typ:     "pod",
+			name:    "name",
+			want:    []string{"po"},
+			wantErr: false,
+		},
+		{
+			typ:     "pods",
+			name:    "name",
+			want:    []string{"po"},
+			wantErr: false,
+		},
+
+		{
+			typ:     "deploy",
+			name:    "",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			typ:     "deploy",
+			name:    "name",
+			want:    []string{"Deployment", "volumes"},
+			wantErr: false,
+		},
+		{
+			typ:     "deployment",
+			name:    "name",
+			want:    []string{"Deployment", "volumes"},
+			wantErr: false,
+		},
+		{
+			typ:     "deployments",
+			name:    "name",
+			want:    []string{"Deployment", "volumes"},
+			wantErr: false,
+		},
+
+		{
+			typ:     "sts",
+			name:    "",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			typ:     "sts",
+			name:    "name",
+			want:    []string{"StatefulSet", "volumes"},
+			wantErr: false,
+		},
+		{
+			typ:     "statefulset",
+			name:    "name",
+			want:    []string{"StatefulSet", "volumes"},
+			wantErr: false,
+		},
+		{
+			typ:     "statefulsets",
+			name:    "name",
+			want:    []string{"StatefulSet", "volumes"},
+			wantErr: false,
+		},
+
+		{
+			typ:     "ds",
+			name:    "",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			typ:     "ds",
+			name:    "name",
+			want:    []string{"DaemonSet", "volumes"},
+			wantErr: false,
+		},
+		{
+			typ:     "daemonset",
+			name:    "name",
+			want:    []string{"DaemonSet", "volumes"},
+			wantErr: false,
+		},
+		{
+			typ:     "daemonsets",
+			name:    "name",
+			want:    []string{"DaemonSet", "volumes"},
+			wantErr: false,
+		},
+
+		{
+			typ:     "rs",
+			name:    "",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			typ:     "rs",
+			name:    "name",
+			want:    []string{"ReplicaSet", "volumes"},
+			wantErr: false,
+		},
+		{
+			typ:     "replicaset",
+			name:    "name",
+			want:    []string{"ReplicaSet", "volumes"},
+			wantErr: false,
+		},
+		{
+			typ:     "replicasets",
+			name:    "name",
+			want:    []string{"ReplicaSet", "volumes"},
+			wantErr: false,
+		},
+
+		{
+			typ:     "job",
+			name:    "",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			typ:     "job",
+			name:    "name",
+			want:    []string{"Job", "volumes"},
+			wantErr: false,
+		},
+		{
+			typ:     "jobs",
+			name:    "name",
+			want:    []string{"Job", "volumes"},
+			wantErr: false,
+		},
+
+		{
+			typ:     "ing",
+			name:    "",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			typ:     "ing",
+			name:    "name",
+			want:    []string{"ing"},
+			wantErr: false,
+		},
+		{
+			typ:     "ingress",
+			name:    "name",
+			want:    []string{"ing"},
+			wantErr: false,
+		},
+		{
+			typ:     "ingresses",
+			name:    "name",
+			want:    []string{"ing"},
+			wantErr: false,
+		},
+
+		{
+			typ:     "gtw",
+			name:    "",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			typ:     "gtw",
+			name:    "name",
+			want:    []string{"gtw"},
+			wantErr: false,
+		},
+		{
+			typ:     "gateway",
+			name:    "name",
+			want:    []string{"gtw"},
+			wantErr: false,
+		},
+		{
+			typ:     "gateways",
+			name:    "name",
+			want:    []string{"gtw"},
+			wantErr: false,
+		},
+		{
+			typ:     "httproute",
+			name:    "name",
+			want:    []string{"httproute"},
+			wantErr: false,
+		},
+		{
+			typ:     "httproutes",
+			name:    "name",
+			want:    []string{"httproute"},
 			wantErr: false,
 		},
 
 		{
 			typ:     "whomst",
 			name:    "",
-			want:    "",
+			want:    nil,
 			wantErr: true,
 		},
 		{
 			typ:     "whomst",
 			name:    "name",
-			want:    "",
+			want:    nil,
 			wantErr: true,
 		},
 		{
 			typ:     "",
 			name:    "",
-			want:    "",
+			want:    nil,
 			wantErr: true,
 		},
 		{
 			typ:     "",
 			name:    "name",
-			want:    "",
+			want:    nil,
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.typ, func(t *testing.T) {
-			got, err := cloudPatherByType(context.Background(), cp, tt.typ, "namespace", tt.name)
+			got, err := cloudPatherByType(context.Background(), cp, nil, tt.typ, "namespace", tt.name, tt.opts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("cloudPatherWithType() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
+			if got := titles(got); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("cloudPatherWithType() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_cloudPatherByTypeWithRESTMapper(t *testing.T) {
+	cp := &NoopCloudPather{}
+
+	discoveryClient := fake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	discoveryClient.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "nodes", SingularName: "node", Kind: "Node", ShortNames: []string{"no"}},
+				{Name: "services", SingularName: "service", Namespaced: true, Kind: "Service", ShortNames: []string{"svc"}},
+				{Name: "configmaps", SingularName: "configmap", Namespaced: true, Kind: "ConfigMap", ShortNames: []string{"cm"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", SingularName: "deployment", Namespaced: true, Kind: "Deployment", ShortNames: []string{"deploy"}},
+			},
+		},
+		{
+			GroupVersion: "networking.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "ingresses", SingularName: "ingress", Namespaced: true, Kind: "Ingress", ShortNames: []string{"ing"}},
+			},
+		},
+	}
+	mapper := newRESTMapper(discoveryClient)
+
+	tests := []struct {
+		typ     string
+		want    []string
+		wantErr string
+	}{
+		{typ: "svc", want: []string{"svc"}},
+		{typ: "Service", want: []string{"svc"}},
+		{typ: "SERVICES", want: []string{"svc"}},
+		{typ: "services.v1.", want: []string{"svc"}},
+		{typ: "Service.v1.", want: []string{"svc"}},
+		{typ: "no", want: []string{"no"}},
+		{typ: "deploy", want: []string{"Deployment", "volumes"}},
+		{typ: "deployments.apps", want: []string{"Deployment", "volumes"}},
+		{typ: "Deployment.v1.apps", want: []string{"Deployment", "volumes"}},
+		{typ: "ingresses.v1.networking.k8s.io", want: []string{"ing"}},
+		{typ: "cluster", want: []string{"cluster"}},
+		{typ: "httproute", want: []string{"httproute"}},
+		{typ: "cm", wantErr: "kind ConfigMap is known to the cluster but has no DigitalOcean mapping"},
+		{typ: "whomst", wantErr: "unknown type whomst"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			got, err := cloudPatherByType(context.Background(), cp, mapper, tt.typ, "namespace", "name", Options{})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("cloudPatherByType() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("cloudPatherByType() error = %v", err)
+				return
+			}
+			if got := titles(got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cloudPatherByType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+)
+
+// manifestExtensions are the files read from a directory, like kubectl does
+var manifestExtensions = []string{".json", ".yaml", ".yml"}
+
+// DecodeObjects reads the Kubernetes objects of YAML or JSON documents,
+// expanding List kinds such as the output of kubectl get -o yaml
+func DecodeObjects(r io.Reader) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+
+	var objects []*unstructured.Unstructured
+	for {
+		var doc map[string]interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return objects, nil
+			}
+			return nil, err
+		}
+		if doc == nil {
+			continue
+		}
+
+		object := &unstructured.Unstructured{Object: doc}
+		if !object.IsList() {
+			objects = append(objects, object)
+			continue
+		}
+
+		err := object.EachListItem(func(item runtime.Object) error {
+			objects = append(objects, item.(*unstructured.Unstructured))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readObjects decodes the objects of files, of the manifests of directories
+// and, for "-", of stdin
+func readObjects(filenames []string, stdin io.Reader) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	for _, filename := range filenames {
+		if filename == "-" {
+			decoded, err := DecodeObjects(stdin)
+			if err != nil {
+				return nil, fmt.Errorf("reading stdin: %w", err)
+			}
+			objects = append(objects, decoded...)
+			continue
+		}
+
+		paths := []string{filename}
+		if info, err := os.Stat(filename); err != nil {
+			return nil, err
+		} else if info.IsDir() {
+			paths, err = manifestsIn(filename)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, path := range paths {
+			decoded, err := decodeFile(path)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, decoded...)
+		}
+	}
+	return objects, nil
+}
+
+func manifestsIn(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		for _, ext := range manifestExtensions {
+			if filepath.Ext(entry.Name()) == ext {
+				paths = append(paths, filepath.Join(dir, entry.Name()))
+				break
+			}
+		}
+	}
+	return paths, nil
+}
+
+func decodeFile(path string) ([]*unstructured.Unstructured, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	objects, err := DecodeObjects(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return objects, nil
+}
+
+// NewOfflineClient returns a Client resolving references against objects
+// instead of a cluster, e.g. to inspect a saved dump without cluster access.
+// clusterID may be empty, in which case cluster and node pool links fail.
+func NewOfflineClient(objects []*unstructured.Unstructured, clusterID string, opts ClientOptions) (*Client, error) {
+	if opts.Options.Bulk() {
+		return nil, fmt.Errorf("selectors and all namespaces cannot be used when resolving objects offline")
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	clientset := fake.NewSimpleClientset()
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	for _, object := range objects {
+		// manifests usually leave the namespace to kubectl apply, which uses the current one
+		if object.GetNamespace() == "" && !clusterScoped(objectGroupKind(object)) {
+			object = object.DeepCopy()
+			object.SetNamespace(namespace)
+		}
+		if object.GroupVersionKind().GroupKind() == extensionsIngress {
+			object = object.DeepCopy()
+			object.SetAPIVersion(networkingGroup + "/v1beta1")
+		}
+		if err := addOfflineObject(clientset, dynamicClient, object); err != nil {
+			return nil, fmt.Errorf("loading %s %s: %w", object.GetKind(), object.GetName(), err)
+		}
+	}
+
+	clientConfig := &restclient.Config{}
+	if clusterID != "" {
+		clientConfig.Host = fmt.Sprintf("https://%s%s", clusterID, hostnameSuffix)
+	}
+
+	cp := &DOCloudPather{
+		clientConfig:  clientConfig,
+		clientset:     clientset,
+		dynamicClient: dynamicClient,
+	}
+	// without a mapper, types are resolved through the resolver aliases
+	return newClient(cp, nil, opts), nil
+}
+
+// dynamicResources are the built-in kinds resolvers read through the dynamic
+// client, by their resource name
+var dynamicResources = map[schema.GroupKind]string{
+	{Group: networkingGroup, Kind: "Ingress"}:      "ingresses",
+	{Group: networkingGroup, Kind: "IngressClass"}: "ingressclasses",
+}
+
+// extensionsIngress is the kind of the Ingresses of older dumps, which have
+// the fields of networking.k8s.io/v1beta1 ones
+var extensionsIngress = schema.GroupKind{Group: "extensions", Kind: "Ingress"}
+
+// objectGroupKind returns the kind of object, reading extensions Ingresses as
+// networking.k8s.io ones
+func objectGroupKind(object *unstructured.Unstructured) schema.GroupKind {
+	groupKind := object.GroupVersionKind().GroupKind()
+	if groupKind == extensionsIngress {
+		groupKind.Group = networkingGroup
+	}
+	return groupKind
+}
+
+// clusterKinds are the cluster-scoped kinds without a resolver that resolvers read
+var clusterKinds = map[schema.GroupKind]bool{
+	{Group: "", Kind: "Namespace"}:                  true,
+	{Group: networkingGroup, Kind: "IngressClass"}:  true,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}: true,
+	{Group: gatewayAPIGroup, Kind: "GatewayClass"}:  true,
+}
+
+// clusterScoped reports whether the objects of a kind live outside namespaces
+func clusterScoped(groupKind schema.GroupKind) bool {
+	if resolver, ok := resolvers.byGroupKind[groupKind]; ok {
+		return resolver.Scope == ScopeCluster
+	}
+	return clusterKinds[groupKind]
+}
+
+// addOfflineObject stores object where the resolvers read it from: the typed
+// clientset for built-in kinds, or the dynamic client for Ingresses and the Gateway API
+func addOfflineObject(clientset *fake.Clientset, dynamicClient *dynamicfake.FakeDynamicClient, object *unstructured.Unstructured) error {
+	gvk := object.GroupVersionKind()
+	resource, dynamic := dynamicResources[gvk.GroupKind()]
+	if gvk.Group == gatewayAPIGroup {
+		resource, dynamic = strings.ToLower(gvk.Kind)+"s", true
+	}
+	if dynamic {
+		gvr := gvk.GroupVersion().WithResource(resource)
+		_, err := dynamicClient.Resource(gvr).Namespace(object.GetNamespace()).Create(context.TODO(), object, metav1.CreateOptions{})
+		return err
+	}
+
+	typed, err := scheme.Scheme.New(gvk)
+	if err != nil {
+		// kinds unknown to client-go have no DigitalOcean mapping
+		return nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, typed); err != nil {
+		return err
+	}
+	typed.GetObjectKind().SetGroupVersionKind(gvk)
+
+	return clientset.Tracker().Add(typed)
+}
+
+// ObjectReferences returns references to the objects that have a DigitalOcean mapping
+func ObjectReferences(objects []*unstructured.Unstructured) []Reference {
+	var refs []Reference
+	for _, object := range objects {
+		resolver, ok := resolvers.byGroupKind[objectGroupKind(object)]
+		if !ok {
+			continue
+		}
+		refs = append(refs, Reference{Namespace: object.GetNamespace(), Type: resolver.Aliases[0], Name: object.GetName()})
+	}
+	return refs
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const offlineDump = `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Node
+  metadata:
+    name: node-1
+  spec:
+    providerID: digitalocean://droplet-id
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config
+    namespace: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: web
+  annotations:
+    kubernetes.digitalocean.com/load-balancer-id: lb-id
+spec:
+  type: LoadBalancer
+status:
+  loadBalancer:
+    ingress:
+    - ip: 203.0.113.10
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  annotations:
+    kubernetes.digitalocean.com/load-balancer-id: api-lb-id
+spec:
+  type: LoadBalancer
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+  namespace: web
+spec:
+  defaultBackend:
+    service:
+      name: web
+      port:
+        number: 80
+status:
+  loadBalancer:
+    ingress:
+    - ip: 203.0.113.10
+---
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: legacy
+  namespace: web
+spec:
+  backend:
+    serviceName: web
+    servicePort: 80
+status:
+  loadBalancer:
+    ingress:
+    - ip: 203.0.113.10
+---
+apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: pv-1
+spec:
+  csi:
+    driver: dobs.csi.digitalocean.com
+    volumeHandle: volume-id
+`
+
+func TestDecodeObjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "yaml documents and lists",
+			manifest: offlineDump,
+			want:     []string{"Node/node-1", "ConfigMap/config", "Service/web", "Service/api", "Ingress/web", "Ingress/legacy", "PersistentVolume/pv-1"},
+		},
+		{
+			name:     "json list",
+			manifest: `{"apiVersion": "v1", "kind": "List", "items": [{"apiVersion": "v1", "kind": "Node", "metadata": {"name": "node-1"}}]}`,
+			want:     []string{"Node/node-1"},
+		},
+		{
+			name:     "empty documents",
+			manifest: "---\n---\n",
+			want:     nil,
+		},
+		{
+			name:     "invalid manifest",
+			manifest: "kind: [",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects, err := DecodeObjects(strings.NewReader(tt.manifest))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeObjects() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var got []string
+			for _, object := range objects {
+				got = append(got, object.GetKind()+"/"+object.GetName())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeObjects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewOfflineClient(t *testing.T) {
+	objects, err := DecodeObjects(strings.NewReader(offlineDump))
+	if err != nil {
+		t.Fatalf("DecodeObjects() error = %v", err)
+	}
+
+	client, err := NewOfflineClient(objects, "cluster-id", ClientOptions{Namespace: "web"})
+	if err != nil {
+		t.Fatalf("NewOfflineClient() error = %v", err)
+	}
+
+	refs := append(ObjectReferences(objects), Reference{Type: "cluster"})
+	wantRefs := []Reference{
+		{Type: "nodes", Name: "node-1"},
+		{Namespace: "web", Type: "services", Name: "web"},
+		{Type: "services", Name: "api"},
+		{Namespace: "web", Type: "ingresses", Name: "web"},
+		{Namespace: "web", Type: "ingresses", Name: "legacy"},
+		{Type: "persistentvolumes", Name: "pv-1"},
+		{Type: "cluster"},
+	}
+	if !reflect.DeepEqual(refs, wantRefs) {
+		t.Fatalf("ObjectReferences() = %v, want %v", refs, wantRefs)
+	}
+
+	want := []string{
+		"droplets/droplet-id",
+		"networking/load_balancers/lb-id",
+		"networking/load_balancers/api-lb-id",
+		"networking/load_balancers/lb-id",
+		"networking/load_balancers/lb-id",
+		"volumes/volume-id",
+		"kubernetes/clusters/cluster-id",
+	}
+	// references to objects without a namespace are looked up in the client namespace
+	refs = append(refs, Reference{Type: "svc", Name: "api"})
+	want = append(want, "networking/load_balancers/api-lb-id")
+	for i, ref := range refs {
+		results := client.Resolve(context.TODO(), ref)
+		if len(results) != 1 || results[0].Err != nil {
+			t.Errorf("Client.Resolve(%s) = %+v", ref, results)
+			continue
+		}
+		if got := paths(results[0].Links); !reflect.DeepEqual(got, []string{want[i]}) {
+			t.Errorf("Client.Resolve(%s) = %v, want %v", ref, got, want[i])
+		}
+	}
+
+	if _, err := NewOfflineClient(objects, "", ClientOptions{Options: Options{AllNamespaces: true}}); err == nil {
+		t.Errorf("NewOfflineClient() with all namespaces did not fail")
+	}
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// overviewQuietPeriod is how long WatchOverview waits for changes to settle
+// before refreshing, so that a rollout does not trigger a refresh per object
+const overviewQuietPeriod = time.Second
+
+// rewatchDelay is how long a failed watch waits before being retried
+const rewatchDelay = 5 * time.Second
+
+// the size of a Load Balancer is set by one of these annotations, the unit one being the most recent
+const lbSizeUnitAnnotation = "service.beta.kubernetes.io/do-loadbalancer-size-unit"
+const lbSizeSlugAnnotation = "service.beta.kubernetes.io/do-loadbalancer-size-slug"
+
+// sizeLabels hold the Droplet size of a node, from the most to the least recent
+var sizeLabels = []string{corev1.LabelInstanceTypeStable, corev1.LabelInstanceType}
+
+type OverviewRunner func(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, opts RunOptions) (*Overview, error)
+
+type OverviewWatcher func(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, opts RunOptions) (<-chan OverviewUpdate, error)
+
+// Overview is a snapshot of the DigitalOcean resources backing a cluster
+type Overview struct {
+	// Cluster is the zero Link when the cluster ID is unknown
+	Cluster   Link
+	NodePools []Link
+	// Nodes are the DigitalOcean-provisioned nodes, linked to their Droplet and node pool
+	Nodes []OverviewEntry
+	// LoadBalancers are the Services of the LoadBalancer type
+	LoadBalancers []OverviewEntry
+	// Volumes are the PersistentVolumes of DigitalOcean Block Storage
+	Volumes []OverviewEntry
+	// Claims are the PersistentVolumeClaims bound to Volumes
+	Claims []OverviewEntry
+	// Notes explain why parts of the overview are missing
+	Notes []string
+}
+
+// OverviewEntry is a Kubernetes object and the DigitalOcean resources backing it
+type OverviewEntry struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Claim is the namespace/name of the claim bound to a PersistentVolume
+	Claim string
+	// Size is the Droplet size slug of a node, the size of a Load Balancer or the capacity of a volume
+	Size string
+	// Pool is the name of the node pool of a node
+	Pool   string
+	Labels map[string]string
+	Links  []Link
+	// Err explains why the object is not linked yet, e.g. while its Load Balancer is provisioned
+	Err error
+}
+
+// OverviewUpdate is a fresh Overview, or the error that prevented building it
+type OverviewUpdate struct {
+	Overview *Overview
+	Err      error
+}
+
+// Overview lists the nodes, Services, PersistentVolumes and their claims and
+// links them the same way Node, PoolOfNode, Service and PersistentVolume do
+func (cp *DOCloudPather) Overview(ctx context.Context) (*Overview, error) {
+	overview := &Overview{}
+
+	clusterID, err := cp.clusterID()
+	if err == nil {
+		overview.Cluster, _ = cp.Cluster(ctx)
+		overview.NodePools, err = cp.NodePool(ctx, "")
+	}
+	if err != nil {
+		overview.Notes = append(overview.Notes, err.Error())
+	}
+
+	nodes, err := cp.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if !strings.HasPrefix(node.Spec.ProviderID, nodeIDPrefix) {
+			continue
+		}
+
+		link, _ := nodeLink(node)
+		entry := OverviewEntry{Kind: "Node", Name: node.Name, Links: []Link{link}, Pool: node.Labels[nodePoolNameLabel], Labels: node.Labels}
+		for _, label := range sizeLabels {
+			if size, ok := node.Labels[label]; ok {
+				entry.Size = size
+				break
+			}
+		}
+		if clusterID != "" {
+			if link, err := nodePoolLink(clusterID, node); err == nil {
+				entry.Links = append(entry.Links, link)
+			}
+		}
+		overview.Nodes = append(overview.Nodes, entry)
+	}
+
+	services, err := cp.clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range services.Items {
+		svc := &services.Items[i]
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+
+		entry := OverviewEntry{Kind: "Service", Namespace: svc.Namespace, Name: svc.Name, Size: svc.Annotations[lbSizeSlugAnnotation], Labels: svc.Labels}
+		if unit, ok := svc.Annotations[lbSizeUnitAnnotation]; ok {
+			entry.Size = unit + " nodes"
+		}
+		if link, err := serviceLink(svc); err != nil {
+			entry.Err = err
+		} else {
+			entry.Links = []Link{link}
+		}
+		overview.LoadBalancers = append(overview.LoadBalancers, entry)
+	}
+
+	pvs, err := cp.clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	volumes := map[string]OverviewEntry{}
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		link, err := volumeLink(pv)
+		if err != nil {
+			// not a DigitalOcean Block Storage Volume
+			continue
+		}
+
+		entry := OverviewEntry{Kind: "PersistentVolume", Name: pv.Name, Links: []Link{link}, Labels: pv.Labels}
+		if ref := pv.Spec.ClaimRef; ref != nil {
+			entry.Claim = ref.Namespace + "/" + ref.Name
+		}
+		if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+			entry.Size = capacity.String()
+		}
+		overview.Volumes = append(overview.Volumes, entry)
+		volumes[pv.Name] = entry
+	}
+
+	pvcs, err := cp.clientset.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pvc := range pvcs.Items {
+		volume, ok := volumes[pvc.Spec.VolumeName]
+		if !ok || pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+
+		overview.Claims = append(overview.Claims, OverviewEntry{
+			Kind:      "PersistentVolumeClaim",
+			Namespace: pvc.Namespace,
+			Name:      pvc.Name,
+			Links:     volume.Links,
+			Size:      volume.Size,
+			Labels:    pvc.Labels,
+		})
+	}
+
+	for _, entries := range [][]OverviewEntry{overview.Nodes, overview.LoadBalancers, overview.Volumes, overview.Claims} {
+		sortEntries(entries)
+	}
+	return overview, nil
+}
+
+func sortEntries(entries []OverviewEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Namespace != entries[j].Namespace {
+			return entries[i].Namespace < entries[j].Namespace
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+// watchChanges signals on the returned channel whenever a node, Service,
+// PersistentVolume or PersistentVolumeClaim changes, until ctx is done. Watches that end are restarted.
+func (cp *DOCloudPather) watchChanges(ctx context.Context) (<-chan struct{}, error) {
+	core := cp.clientset.CoreV1()
+	watchers := []func(context.Context, metav1.ListOptions) (watch.Interface, error){
+		core.Nodes().Watch,
+		core.Services("").Watch,
+		core.PersistentVolumes().Watch,
+		core.PersistentVolumeClaims("").Watch,
+	}
+
+	changes := make(chan struct{}, 1)
+	for _, watchFunc := range watchers {
+		// the first watch is started right away so that errors are returned and no change is missed
+		w, err := watchFunc(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		go func(w watch.Interface, watchFunc func(context.Context, metav1.ListOptions) (watch.Interface, error)) {
+			for {
+				for range w.ResultChan() {
+					select {
+					case changes <- struct{}{}:
+					default:
+					}
+				}
+				w.Stop()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(rewatchDelay):
+					}
+
+					var err error
+					if w, err = watchFunc(ctx, metav1.ListOptions{}); err == nil {
+						break
+					}
+				}
+				// changes may have been missed while the watch was down
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+		}(w, watchFunc)
+	}
+	return changes, nil
+}
+
+// Overview returns a snapshot of the DigitalOcean resources backing the cluster
+func (c *Client) Overview(ctx context.Context) (*Overview, error) {
+	overview, err := c.cp.Overview(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if overview.Cluster.URL != "" {
+		overview.Cluster = overview.Cluster.rebased(c.opts.CloudBase)
+	}
+	rebaseLinks(overview.NodePools, c.opts.CloudBase)
+	// claims share the links of their volume
+	for _, entries := range [][]OverviewEntry{overview.Nodes, overview.LoadBalancers, overview.Volumes} {
+		for _, entry := range entries {
+			rebaseLinks(entry.Links, c.opts.CloudBase)
+		}
+	}
+	return overview, nil
+}
+
+func rebaseLinks(links []Link, base string) {
+	for i, link := range links {
+		links[i] = link.rebased(base)
+	}
+}
+
+// WatchOverview sends an Overview right away, then a fresh one each time the
+// nodes, Services or volumes of the cluster change, until ctx is done
+func (c *Client) WatchOverview(ctx context.Context) (<-chan OverviewUpdate, error) {
+	changes, err := c.cp.watchChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan OverviewUpdate)
+	go func() {
+		defer close(updates)
+		for {
+			overview, err := c.Overview(ctx)
+			select {
+			case updates <- OverviewUpdate{Overview: overview, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-changes:
+			case <-ctx.Done():
+				return
+			}
+			// let a burst of changes settle
+			for settled := false; !settled; {
+				select {
+				case <-changes:
+				case <-time.After(overviewQuietPeriod):
+					settled = true
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return updates, nil
+}
+
+// FetchOverview returns a snapshot of the DigitalOcean resources backing the
+// cluster kubeConfig points at, or the objects of opts.Filenames
+func FetchOverview(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, opts RunOptions) (*Overview, error) {
+	if opts.AllContexts {
+		return nil, fmt.Errorf("an overview covers a single context")
+	}
+
+	client, _, err := clientFor(writer, kubeConfig, "", opts)
+	if err != nil {
+		return nil, err
+	}
+	return client.Overview(ctx)
+}
+
+// WatchOverview keeps sending fresh Overviews of the cluster kubeConfig points
+// at, or of the objects of opts.Filenames, until ctx is done
+func WatchOverview(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, opts RunOptions) (<-chan OverviewUpdate, error) {
+	if opts.AllContexts {
+		return nil, fmt.Errorf("an overview covers a single context")
+	}
+
+	client, _, err := clientFor(writer, kubeConfig, "", opts)
+	if err != nil {
+		return nil, err
+	}
+	return client.WatchOverview(ctx)
+}
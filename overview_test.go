@@ -0,0 +1,179 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+)
+
+func TestDOCloudPather_Overview(t *testing.T) {
+	ctx := context.TODO()
+	cp := newFakeDOCloudPather()
+	cp.clientConfig = &restclient.Config{Host: "https://cluster-id" + hostnameSuffix}
+
+	cp.clientset.CoreV1().Nodes().Create(ctx, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{nodePoolIDLabel: "pool-id", nodePoolNameLabel: "web", corev1.LabelInstanceTypeStable: "s-2vcpu-4gb"}},
+		Spec:       corev1.NodeSpec{ProviderID: nodeIDPrefix + "123"},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Nodes().Create(ctx, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "kind-node"},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Services("web").Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "web", Annotations: map[string]string{lbaasAnnotation: "lb-id", lbSizeUnitAnnotation: "3"}},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Services("api").Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "api"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Services("web").Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "web"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumes().Create(ctx, &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: csiDriverName, VolumeHandle: "volume-id"},
+			},
+			ClaimRef: &corev1.ObjectReference{Namespace: "web", Name: "data"},
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+		},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumeClaims("web").Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "web"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumeClaims("web").Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "web"},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumes().Create(ctx, &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "local"},
+		Spec:       corev1.PersistentVolumeSpec{StorageClassName: "local-path"},
+	}, metav1.CreateOptions{})
+
+	got, err := cp.Overview(ctx)
+	if err != nil {
+		t.Fatalf("DOCloudPather.Overview() error = %v", err)
+	}
+
+	if got.Cluster.path() != "kubernetes/clusters/cluster-id" {
+		t.Errorf("DOCloudPather.Overview() cluster = %s", got.Cluster.URL)
+	}
+	if want := []string{"kubernetes/clusters/cluster-id/nodepools/pool-id"}; !reflect.DeepEqual(paths(got.NodePools), want) {
+		t.Errorf("DOCloudPather.Overview() node pools = %v, want %v", paths(got.NodePools), want)
+	}
+
+	tests := []struct {
+		name    string
+		entries []OverviewEntry
+		want    []string
+	}{
+		{
+			name:    "nodes",
+			entries: got.Nodes,
+			want:    []string{"/node-1 [droplets/123 kubernetes/clusters/cluster-id/nodepools/pool-id] size s-2vcpu-4gb pool web"},
+		},
+		{
+			name:    "load balancers",
+			entries: got.LoadBalancers,
+			want:    []string{"api/api [] annotation " + lbaasAnnotation + " not found on service", "web/web [networking/load_balancers/lb-id] size 3 nodes"},
+		},
+		{
+			name:    "volumes",
+			entries: got.Volumes,
+			want:    []string{"/pv-1 claim web/data [volumes/volume-id] size 10Gi"},
+		},
+		{
+			name:    "claims",
+			entries: got.Claims,
+			want:    []string{"web/data [volumes/volume-id] size 10Gi"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []string
+			for _, entry := range tt.entries {
+				s := entry.Namespace + "/" + entry.Name
+				if entry.Claim != "" {
+					s += " claim " + entry.Claim
+				}
+				s += fmt.Sprintf(" %v", paths(entry.Links))
+				if entry.Err != nil {
+					s += " " + entry.Err.Error()
+				}
+				if entry.Size != "" {
+					s += " size " + entry.Size
+				}
+				if entry.Pool != "" {
+					s += " pool " + entry.Pool
+				}
+				entries = append(entries, s)
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("DOCloudPather.Overview() %s = %q, want %q", tt.name, entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_WatchOverview(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	cp := newFakeDOCloudPather()
+	cp.clientConfig = &restclient.Config{Host: "https://cluster-id" + hostnameSuffix}
+	client := newClient(cp, nil, ClientOptions{CloudBase: "https://cloud.example.com"})
+
+	updates, err := client.WatchOverview(ctx)
+	if err != nil {
+		t.Fatalf("Client.WatchOverview() error = %v", err)
+	}
+
+	next := func() *Overview {
+		select {
+		case update := <-updates:
+			if update.Err != nil {
+				t.Fatalf("Client.WatchOverview() update error = %v", update.Err)
+			}
+			return update.Overview
+		case <-time.After(5 * time.Second):
+			t.Fatal("Client.WatchOverview() sent no update")
+		}
+		return nil
+	}
+
+	if overview := next(); len(overview.Nodes) != 0 {
+		t.Errorf("Client.WatchOverview() first nodes = %v, want none", overview.Nodes)
+	}
+
+	cp.clientset.CoreV1().Nodes().Create(ctx, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       corev1.NodeSpec{ProviderID: nodeIDPrefix + "123"},
+	}, metav1.CreateOptions{})
+	overview := next()
+	if len(overview.Nodes) != 1 || overview.Nodes[0].Links[0].URL != "https://cloud.example.com/droplets/123" {
+		t.Errorf("Client.WatchOverview() nodes after a change = %+v", overview.Nodes)
+	}
+}
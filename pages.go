@@ -0,0 +1,142 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// resourcePages describes the tabs of a control panel page. Paths are matched
+// by their segments, with an empty segment matching any ID.
+type resourcePages struct {
+	kind     string
+	segments []string
+	tabs     map[string]string
+}
+
+var pagesByResource = []resourcePages{
+	{
+		kind:     "cluster",
+		segments: []string{"kubernetes", "clusters", ""},
+		tabs: map[string]string{
+			"overview":    "",
+			"insights":    "insights",
+			"resources":   "resources",
+			"marketplace": "marketplace",
+			"nodes":       "nodes",
+			"upgrades":    "upgrades",
+			"settings":    "settings",
+		},
+	},
+	{
+		kind:     "Droplet",
+		segments: []string{"droplets", ""},
+		tabs: map[string]string{
+			"overview": "",
+			"graphs":   "graphs",
+			"access":   "access",
+			"console":  "terminal/ui/",
+		},
+	},
+	{
+		kind:     "Load Balancer",
+		segments: []string{"networking", "load_balancers", ""},
+		tabs: map[string]string{
+			"overview": "",
+			"graphs":   "graphs",
+			"settings": "settings",
+		},
+	},
+}
+
+// withPage points each link at the given tab of its page. Links whose page
+// does not have that tab are kept as they are, with a note, as long as at
+// least one of the links has it.
+func withPage(links []Link, page string) ([]Link, error) {
+	if page == "" {
+		return links, nil
+	}
+
+	paged := make([]Link, 0, len(links))
+	var pageErr error
+	found := false
+	for _, link := range links {
+		path := link.path()
+		tab, err := pageTab(path, page)
+		if err != nil {
+			if pageErr == nil {
+				pageErr = err
+			}
+			notes := append([]string(nil), link.Notes...)
+			link.Notes = append(notes, fmt.Sprintf("opening the default page because %s", err))
+			paged = append(paged, link)
+			continue
+		}
+
+		found = true
+		if tab != "" {
+			link.URL = fmt.Sprintf("%s%s/%s", DefaultCloudBase, path, tab)
+		}
+		paged = append(paged, link)
+	}
+
+	if !found {
+		return nil, pageErr
+	}
+	return paged, nil
+}
+
+// pageTab returns the tab of the page of path with the given name
+func pageTab(path, page string) (string, error) {
+	pages := pagesOf(path)
+	if pages == nil {
+		return "", fmt.Errorf("page %s was requested but %s does not have any pages", page, path)
+	}
+
+	tab, ok := pages.tabs[page]
+	if !ok {
+		return "", fmt.Errorf("unknown page %s for a %s, must be one of: %s", page, pages.kind, strings.Join(pages.names(), ", "))
+	}
+	return tab, nil
+}
+
+func pagesOf(path string) *resourcePages {
+	segments := strings.Split(path, "/")
+
+outer:
+	for i, pages := range pagesByResource {
+		if len(segments) != len(pages.segments) {
+			continue
+		}
+		for j, segment := range pages.segments {
+			if segment != "" && segment != segments[j] {
+				continue outer
+			}
+		}
+		return &pagesByResource[i]
+	}
+
+	return nil
+}
+
+func (p *resourcePages) names() []string {
+	names := make([]string, 0, len(p.tabs))
+	for name := range p.tabs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
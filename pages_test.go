@@ -0,0 +1,129 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_withPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		paths     []string
+		page      string
+		want      []string
+		wantNoted []string
+		wantErr   bool
+	}{
+		{
+			name:    "no page",
+			paths:   []string{"volumes/id"},
+			page:    "",
+			want:    []string{"volumes/id"},
+			wantErr: false,
+		},
+		{
+			name:    "cluster insights",
+			paths:   []string{"kubernetes/clusters/id"},
+			page:    "insights",
+			want:    []string{"kubernetes/clusters/id/insights"},
+			wantErr: false,
+		},
+		{
+			name:    "cluster upgrades",
+			paths:   []string{"kubernetes/clusters/id"},
+			page:    "upgrades",
+			want:    []string{"kubernetes/clusters/id/upgrades"},
+			wantErr: false,
+		},
+		{
+			name:    "cluster overview",
+			paths:   []string{"kubernetes/clusters/id"},
+			page:    "overview",
+			want:    []string{"kubernetes/clusters/id"},
+			wantErr: false,
+		},
+		{
+			name:    "droplet console",
+			paths:   []string{"droplets/1", "droplets/2"},
+			page:    "console",
+			want:    []string{"droplets/1/terminal/ui/", "droplets/2/terminal/ui/"},
+			wantErr: false,
+		},
+		{
+			name:    "load balancer settings",
+			paths:   []string{"networking/load_balancers/id"},
+			page:    "settings",
+			want:    []string{"networking/load_balancers/id/settings"},
+			wantErr: false,
+		},
+		{
+			name:    "unknown page",
+			paths:   []string{"droplets/1"},
+			page:    "whomst",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:      "resource without pages next to a droplet",
+			paths:     []string{"droplets/1", "volumes/id"},
+			page:      "graphs",
+			want:      []string{"droplets/1/graphs", "volumes/id"},
+			wantNoted: []string{"volumes/id"},
+			wantErr:   false,
+		},
+		{
+			name:    "resources without pages",
+			paths:   []string{"volumes/id", "volumes/other-id"},
+			page:    "graphs",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "node pool of a cluster",
+			paths:   []string{"kubernetes/clusters/id/nodepools/pool-id"},
+			page:    "insights",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var links []Link
+			for _, path := range tt.paths {
+				links = append(links, Link{URL: DefaultCloudBase + path})
+			}
+
+			got, err := withPage(links, tt.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("withPage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := paths(got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withPage() = %v, want %v", got, tt.want)
+			}
+
+			var noted []string
+			for _, link := range got {
+				if len(link.Notes) > 0 {
+					noted = append(noted, link.path())
+				}
+			}
+			if !reflect.DeepEqual(noted, tt.wantNoted) {
+				t.Errorf("withPage() noted %v, want %v", noted, tt.wantNoted)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+// RecordListVersion is the apiVersion of the json and yaml outputs. Fields are
+// only ever added to LinkRecord within a version.
+const RecordListVersion = "doweb.digitalocean.com/v1"
+
+// LinkRecordList is the document printed by the json and yaml outputs
+type LinkRecordList struct {
+	APIVersion string       `json:"apiVersion"`
+	Kind       string       `json:"kind"`
+	Items      []LinkRecord `json:"items"`
+}
+
+// LinkRecord describes a DigitalOcean resource a Kubernetes object resolved to
+type LinkRecord struct {
+	Kubernetes KubernetesRecord `json:"kubernetes"`
+	// Type is the DigitalOcean resource type: kubernetes_cluster,
+	// kubernetes_node_pool, droplet, load_balancer or volume
+	Type string `json:"type"`
+	// ID is empty when the resource could only be resolved to a list page
+	ID       string   `json:"id,omitempty"`
+	Title    string   `json:"title"`
+	URL      string   `json:"url"`
+	Region   string   `json:"region,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// KubernetesRecord identifies the Kubernetes object a LinkRecord was resolved from
+type KubernetesRecord struct {
+	// Context is only set when searching every context of the kube config
+	Context   string `json:"context,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// NewLinkRecordList builds the records of every successfully resolved result
+func NewLinkRecordList(results []Result) LinkRecordList {
+	list := LinkRecordList{
+		APIVersion: RecordListVersion,
+		Kind:       "LinkList",
+		Items:      []LinkRecord{},
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		for _, link := range result.Links {
+			list.Items = append(list.Items, LinkRecord{
+				Kubernetes: KubernetesRecord{
+					Context:   result.Context,
+					Kind:      result.Kind,
+					Namespace: result.Reference.Namespace,
+					Name:      result.Reference.Name,
+				},
+				Type:     string(link.Kind),
+				ID:       link.ID,
+				Title:    link.Title,
+				URL:      link.URL,
+				Region:   link.Region,
+				Warnings: link.Notes,
+			})
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewLinkRecordList(t *testing.T) {
+	droplet := newLink(ResourceDroplet, "1", "Droplet 1 (node node-1)", "droplets/1")
+	droplet.Region = "nyc1"
+	legacy := volumesFallback("PersistentVolume legacy", "it is not provisioned by the dobs.csi.digitalocean.com CSI driver")
+
+	results := []Result{
+		{
+			Reference: Reference{Namespace: "ns", Type: "sts", Name: "db"},
+			Kind:      "StatefulSet",
+			Links: []Link{
+				droplet,
+				newLink(ResourceVolume, "vol-id", "Volume vol-id (PersistentVolume pv-1)", "volumes/vol-id"),
+			},
+		},
+		{
+			Reference: Reference{Type: "pv", Name: "legacy"},
+			Kind:      "PersistentVolume",
+			Links:     []Link{legacy},
+		},
+		{
+			Reference: Reference{Namespace: "ns", Type: "svc", Name: "web"},
+			Kind:      "Service",
+			Links:     []Link{newLink(ResourceLoadBalancer, "lb-id", "Load Balancer lb-id (Service ns/web)", "networking/load_balancers/lb-id")},
+		},
+		{
+			Reference: Reference{Namespace: "ns", Type: "svc", Name: "internal"},
+			Kind:      "Service",
+			Err:       errors.New("not a LoadBalancer"),
+		},
+	}
+
+	want := LinkRecordList{
+		APIVersion: RecordListVersion,
+		Kind:       "LinkList",
+		Items: []LinkRecord{
+			{
+				Kubernetes: KubernetesRecord{Kind: "StatefulSet", Namespace: "ns", Name: "db"},
+				Type:       "droplet",
+				ID:         "1",
+				Title:      "Droplet 1 (node node-1)",
+				URL:        DefaultCloudBase + "droplets/1",
+				Region:     "nyc1",
+			},
+			{
+				Kubernetes: KubernetesRecord{Kind: "StatefulSet", Namespace: "ns", Name: "db"},
+				Type:       "volume",
+				ID:         "vol-id",
+				Title:      "Volume vol-id (PersistentVolume pv-1)",
+				URL:        DefaultCloudBase + "volumes/vol-id",
+			},
+			{
+				Kubernetes: KubernetesRecord{Kind: "PersistentVolume", Name: "legacy"},
+				Type:       "volume",
+				Title:      "Volumes (PersistentVolume legacy)",
+				URL:        DefaultCloudBase + "volumes",
+				Warnings:   []string{"opening the Volumes list page because it is not provisioned by the dobs.csi.digitalocean.com CSI driver"},
+			},
+			{
+				Kubernetes: KubernetesRecord{Kind: "Service", Namespace: "ns", Name: "web"},
+				Type:       "load_balancer",
+				ID:         "lb-id",
+				Title:      "Load Balancer lb-id (Service ns/web)",
+				URL:        DefaultCloudBase + "networking/load_balancers/lb-id",
+			},
+		},
+	}
+
+	got := NewLinkRecordList(results)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewLinkRecordList() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewLinkRecordList_namespaces(t *testing.T) {
+	ctx := context.TODO()
+	cp := newFakeDOCloudPather()
+	cp.clientset.CoreV1().Services("payments").Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "payments", Annotations: map[string]string{lbaasAnnotation: "lb-id"}},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Nodes().Create(ctx, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       corev1.NodeSpec{ProviderID: nodeIDPrefix + "1"},
+	}, metav1.CreateOptions{})
+	client := newClient(cp, nil, ClientOptions{Namespace: "payments"})
+
+	tests := []struct {
+		name string
+		ref  Reference
+		want KubernetesRecord
+	}{
+		{
+			name: "namespace of the client",
+			ref:  Reference{Type: "svc", Name: "web"},
+			want: KubernetesRecord{Kind: "Service", Namespace: "payments", Name: "web"},
+		},
+		{
+			name: "cluster-scoped object",
+			ref:  Reference{Type: "node", Name: "node-1"},
+			want: KubernetesRecord{Kind: "Node", Name: "node-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := NewLinkRecordList(client.Resolve(ctx, tt.ref))
+			if len(list.Items) != 1 {
+				t.Fatalf("NewLinkRecordList() = %d items, want 1", len(list.Items))
+			}
+			if got := list.Items[0].Kubernetes; got != tt.want {
+				t.Errorf("NewLinkRecordList() kubernetes = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+// Reference names a Kubernetes object by its type and name. An empty
+// namespace stands for the default namespace.
+type Reference struct {
+	Namespace string
+	Type      string
+	Name      string
+}
+
+func (r Reference) String() string {
+	if r.Name == "" {
+		return r.Type
+	}
+	return fmt.Sprintf("%s/%s", r.Type, r.Name)
+}
+
+// Result holds the links a Reference resolved to, or why it could not be resolved
+type Result struct {
+	Reference Reference
+	// Kind is the kind of the referenced object, when its type is known
+	Kind string
+	// Context is the kube context the object was found in when searching all of them
+	Context string
+	Links   []Link
+	Err     error
+}
+
+// ParseReferences parses command line arguments given either as a type followed
+// by any number of names, or as any number of type/name references like the
+// output of kubectl get -o name.
+func ParseReferences(args []string) ([]Reference, error) {
+	if len(args) == 0 {
+		return nil, ErrMissingArgument
+	}
+
+	if !strings.Contains(args[0], "/") {
+		if len(args) == 1 {
+			return []Reference{{Type: args[0]}}, nil
+		}
+
+		refs := make([]Reference, 0, len(args)-1)
+		for _, name := range args[1:] {
+			if strings.Contains(name, "/") {
+				return nil, fmt.Errorf("there is no need to specify a type as a separate argument when passing arguments in type/name form (e.g. 'kubectl doweb %s' instead of 'kubectl doweb %s %s')", name, args[0], name)
+			}
+			refs = append(refs, Reference{Type: args[0], Name: name})
+		}
+		return refs, nil
+	}
+
+	refs := make([]Reference, 0, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("arguments in type/name form must have a single type and name, got %s", arg)
+		}
+		refs = append(refs, Reference{Type: parts[0], Name: parts[1]})
+	}
+	return refs, nil
+}
+
+// ReadReferences reads newline-separated references in the type/name form of
+// kubectl get -o name, or in the namespace/type/name form. Blank lines are ignored.
+func ReadReferences(r io.Reader) ([]Reference, error) {
+	var refs []Reference
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		parts := strings.Split(text, "/")
+		for _, part := range parts {
+			if part == "" {
+				parts = nil
+				break
+			}
+		}
+
+		switch len(parts) {
+		case 2:
+			refs = append(refs, Reference{Type: parts[0], Name: parts[1]})
+		case 3:
+			refs = append(refs, Reference{Namespace: parts[0], Type: parts[1], Name: parts[2]})
+		default:
+			return nil, fmt.Errorf("line %d: references must be in type/name or namespace/type/name form, got %s", line, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+// listReferences lists the objects of a type matching the selectors in opts
+func listReferences(ctx context.Context, dynamicClient dynamic.Interface, mapper meta.RESTMapper, typ, namespace string, opts Options) ([]Reference, error) {
+	gvk, err := kindFor(mapper, typ)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := resolvers.byGroupKind[gvk.GroupKind()]; !ok {
+		return nil, fmt.Errorf("kind %s is known to the cluster but has no DigitalOcean mapping", gvk.GroupKind())
+	}
+
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.AllNamespaces || mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		namespace = metav1.NamespaceAll
+	}
+
+	list, err := dynamicClient.Resource(mapping.Resource).Namespace(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: opts.LabelSelector,
+		FieldSelector: opts.FieldSelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]Reference, 0, len(list.Items))
+	for _, item := range list.Items {
+		refs = append(refs, Reference{Namespace: item.GetNamespace(), Type: typ, Name: item.GetName()})
+	}
+	return refs, nil
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestParseReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []Reference
+		wantErr bool
+	}{
+		{
+			name:    "no arguments",
+			args:    nil,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "type only",
+			args:    []string{"cluster"},
+			want:    []Reference{{Type: "cluster"}},
+			wantErr: false,
+		},
+		{
+			name:    "type and name",
+			args:    []string{"svc", "web"},
+			want:    []Reference{{Type: "svc", Name: "web"}},
+			wantErr: false,
+		},
+		{
+			name:    "type and names",
+			args:    []string{"svc", "web", "api"},
+			want:    []Reference{{Type: "svc", Name: "web"}, {Type: "svc", Name: "api"}},
+			wantErr: false,
+		},
+		{
+			name:    "type/name references",
+			args:    []string{"svc/web", "node/pool-1-abc", "deployment.apps/api"},
+			want:    []Reference{{Type: "svc", Name: "web"}, {Type: "node", Name: "pool-1-abc"}, {Type: "deployment.apps", Name: "api"}},
+			wantErr: false,
+		},
+		{
+			name:    "type followed by type/name reference",
+			args:    []string{"svc", "svc/web"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "type/name reference followed by a name",
+			args:    []string{"svc/web", "api"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			args:    []string{"svc/"},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReferences(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseReferences() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseReferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Reference
+		wantErr bool
+	}{
+		{
+			name:    "kubectl get -o name output",
+			input:   "service/web\nservice/api\ndeployment.apps/worker\n",
+			want:    []Reference{{Type: "service", Name: "web"}, {Type: "service", Name: "api"}, {Type: "deployment.apps", Name: "worker"}},
+			wantErr: false,
+		},
+		{
+			name:    "namespaced references and blank lines",
+			input:   "payments/svc/web\n\n  node/pool-1-abc  \n",
+			want:    []Reference{{Namespace: "payments", Type: "svc", Name: "web"}, {Type: "node", Name: "pool-1-abc"}},
+			wantErr: false,
+		},
+		{
+			name:    "empty input",
+			input:   "",
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "type only",
+			input:   "svc/web\ncluster\n",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			input:   "payments/svc/\n",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadReferences(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadReferences() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadReferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_listReferences(t *testing.T) {
+	newObject := func(kind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetLabels(labels)
+		return obj
+	}
+	payments := map[string]string{"team": "payments"}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newObject("Service", "ns", "web", payments),
+		newObject("Service", "ns", "api", nil),
+		newObject("Service", "other", "checkout", payments),
+		newObject("Node", "", "node-1", nil),
+		newObject("ConfigMap", "ns", "config", nil),
+	)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Service"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Node"), meta.RESTScopeRoot)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+
+	tests := []struct {
+		name    string
+		typ     string
+		opts    Options
+		want    []Reference
+		wantErr bool
+	}{
+		{
+			name: "label selector in namespace",
+			typ:  "service",
+			opts: Options{LabelSelector: "team=payments"},
+			want: []Reference{
+				{Namespace: "ns", Type: "service", Name: "web"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "label selector in all namespaces",
+			typ:  "service",
+			opts: Options{LabelSelector: "team=payments", AllNamespaces: true},
+			want: []Reference{
+				{Namespace: "ns", Type: "service", Name: "web"},
+				{Namespace: "other", Type: "service", Name: "checkout"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cluster-scoped type",
+			typ:  "node",
+			opts: Options{AllNamespaces: true},
+			want: []Reference{
+				{Type: "node", Name: "node-1"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "kind without a DigitalOcean mapping",
+			typ:     "configmap",
+			opts:    Options{AllNamespaces: true},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			typ:     "whomst",
+			opts:    Options{AllNamespaces: true},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := listReferences(context.TODO(), dynamicClient, mapper, tt.typ, "ns", tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("listReferences() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("listReferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
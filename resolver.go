@@ -0,0 +1,298 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+)
+
+// Scope tells whether the objects of a kind live in a namespace
+type Scope int
+
+const (
+	ScopeNamespaced Scope = iota
+	ScopeCluster
+)
+
+// ResolveFunc returns the cloud.digitalocean.com links for the named object.
+// Cluster-scoped resolvers are always passed an empty namespace. Resolvers
+// that read objects of their own use the clients of cp.Clients().
+type ResolveFunc func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error)
+
+// CandidatesFunc returns the names of the objects of a kind that are backed by
+// DigitalOcean resources, to choose from when no name is given
+type CandidatesFunc func(ctx context.Context, cp CloudPather, namespace string) ([]string, error)
+
+// Resolver maps a kind of Kubernetes object to DigitalOcean resources
+type Resolver struct {
+	GVK schema.GroupVersionKind
+	// Aliases are the type names accepted on the command line, at least one is
+	// required
+	Aliases []string
+	Scope   Scope
+	// NameOptional is set when the resolver can be called without an object name
+	NameOptional bool
+	Resolve      ResolveFunc
+	// Candidates is optional, kinds without it cannot be picked from a list
+	Candidates CandidatesFunc
+}
+
+// pseudoGroup is the API group of kinds that have no Kubernetes object
+const pseudoGroup = "doweb.digitalocean.com"
+
+type registry struct {
+	byGVK       map[schema.GroupVersionKind]*Resolver
+	byGroupKind map[schema.GroupKind]*Resolver
+	byAlias     map[string]*Resolver
+}
+
+func newRegistry() *registry {
+	return &registry{
+		byGVK:       map[schema.GroupVersionKind]*Resolver{},
+		byGroupKind: map[schema.GroupKind]*Resolver{},
+		byAlias:     map[string]*Resolver{},
+	}
+}
+
+func (r *registry) register(resolver Resolver) error {
+	if resolver.Resolve == nil {
+		return fmt.Errorf("resolver for %s has no resolve function", resolver.GVK)
+	}
+	// references, such as those of offline objects, are built from the first alias
+	if len(resolver.Aliases) == 0 {
+		return fmt.Errorf("resolver for %s has no alias", resolver.GVK)
+	}
+	if _, ok := r.byGVK[resolver.GVK]; ok {
+		return fmt.Errorf("a resolver for %s is already registered", resolver.GVK)
+	}
+	for _, alias := range resolver.Aliases {
+		if existing, ok := r.byAlias[alias]; ok {
+			return fmt.Errorf("alias %s of %s is already used by %s", alias, resolver.GVK, existing.GVK)
+		}
+	}
+
+	r.byGVK[resolver.GVK] = &resolver
+	r.byGroupKind[resolver.GVK.GroupKind()] = &resolver
+	for _, alias := range resolver.Aliases {
+		r.byAlias[alias] = &resolver
+	}
+	return nil
+}
+
+func (r *registry) lookup(typ string) *Resolver {
+	return r.byAlias[typ]
+}
+
+// resolverFor maps a type argument to its resolver. The type is resolved with
+// the cluster's RESTMapper the same way kubectl get does, falling back to the
+// registered aliases when the cluster doesn't know the type. A nil resolver
+// and error are returned for unknown types.
+func (r *registry) resolverFor(typ string, mapper meta.RESTMapper) (*Resolver, error) {
+	// kinds without a Kubernetes object are never known to the cluster
+	if resolver := r.lookup(typ); resolver != nil && resolver.GVK.Group == pseudoGroup {
+		return resolver, nil
+	}
+
+	if mapper != nil {
+		if gvk, err := kindFor(mapper, typ); err == nil {
+			resolver, ok := r.byGroupKind[gvk.GroupKind()]
+			if !ok {
+				return nil, fmt.Errorf("kind %s is known to the cluster but has no DigitalOcean mapping", gvk.GroupKind())
+			}
+			return resolver, nil
+		}
+	}
+
+	return r.lookup(typ), nil
+}
+
+// kindFor mirrors how kubectl maps a resource or kind argument to a kind
+func kindFor(mapper meta.RESTMapper, typ string) (schema.GroupVersionKind, error) {
+	fullySpecifiedGVR, groupResource := schema.ParseResourceArg(typ)
+	gvk := schema.GroupVersionKind{}
+	if fullySpecifiedGVR != nil {
+		gvk, _ = mapper.KindFor(*fullySpecifiedGVR)
+	}
+	if gvk.Empty() {
+		gvk, _ = mapper.KindFor(groupResource.WithVersion(""))
+	}
+	if !gvk.Empty() {
+		return gvk, nil
+	}
+
+	fullySpecifiedGVK, groupKind := schema.ParseKindArg(typ)
+	if fullySpecifiedGVK == nil {
+		gvk := groupKind.WithVersion("")
+		fullySpecifiedGVK = &gvk
+	}
+	if !fullySpecifiedGVK.Empty() {
+		if mapping, err := mapper.RESTMapping(fullySpecifiedGVK.GroupKind(), fullySpecifiedGVK.Version); err == nil {
+			return mapping.GroupVersionKind, nil
+		}
+	}
+
+	mapping, err := mapper.RESTMapping(groupKind)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return mapping.GroupVersionKind, nil
+}
+
+// newRESTMapper returns a RESTMapper backed by the cluster's discovery API,
+// with the short names expanded
+func newRESTMapper(discoveryClient discovery.DiscoveryInterface) meta.RESTMapper {
+	cachedClient := memory.NewMemCacheClient(discoveryClient)
+	return restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(cachedClient), cachedClient)
+}
+
+var resolvers = newRegistry()
+
+// Register adds a resolver for a new kind. It panics if the kind or one of its
+// aliases is already registered, so it is meant to be called from init functions.
+func Register(resolver Resolver) {
+	if err := resolvers.register(resolver); err != nil {
+		panic(err)
+	}
+}
+
+func init() {
+	for _, resolver := range builtinResolvers {
+		Register(resolver)
+	}
+}
+
+var builtinResolvers = []Resolver{
+	{
+		GVK:          schema.GroupVersionKind{Group: pseudoGroup, Version: "v1", Kind: "Cluster"},
+		Aliases:      []string{"cluster"},
+		Scope:        ScopeCluster,
+		NameOptional: true,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			return single(cp.Cluster(ctx))
+		},
+	},
+	{
+		GVK:          schema.GroupVersionKind{Group: pseudoGroup, Version: "v1", Kind: "NodePool"},
+		Aliases:      []string{"nodepools", "nodepool", "np"},
+		Scope:        ScopeCluster,
+		NameOptional: true,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			return cp.NodePool(ctx, name)
+		},
+	},
+	{
+		GVK:     corev1.SchemeGroupVersion.WithKind("Node"),
+		Aliases: []string{"nodes", "node", "no"},
+		Scope:   ScopeCluster,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			if opts.Pool {
+				return single(cp.PoolOfNode(ctx, name))
+			}
+			return single(cp.Node(ctx, name))
+		},
+		Candidates: nodeCandidates,
+	},
+	{
+		GVK:     corev1.SchemeGroupVersion.WithKind("Pod"),
+		Aliases: []string{"pods", "pod", "po"},
+		Scope:   ScopeNamespaced,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			return cp.Pod(ctx, namespace, name)
+		},
+	},
+	{
+		GVK:     corev1.SchemeGroupVersion.WithKind("Service"),
+		Aliases: []string{"services", "service", "svc"},
+		Scope:   ScopeNamespaced,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			return single(cp.Service(ctx, namespace, name))
+		},
+		Candidates: serviceCandidates,
+	},
+	{
+		GVK:     schema.GroupVersionKind{Group: networkingGroup, Version: "v1", Kind: "Ingress"},
+		Aliases: []string{"ingresses", "ingress", "ing"},
+		Scope:   ScopeNamespaced,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			return single(cp.Ingress(ctx, namespace, name))
+		},
+	},
+	{
+		GVK:     schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1", Kind: "Gateway"},
+		Aliases: []string{"gateways", "gateway", "gtw"},
+		Scope:   ScopeNamespaced,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			return single(cp.Gateway(ctx, namespace, name))
+		},
+	},
+	{
+		GVK:     schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1", Kind: "HTTPRoute"},
+		Aliases: []string{"httproutes", "httproute"},
+		Scope:   ScopeNamespaced,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			return cp.HTTPRoute(ctx, namespace, name)
+		},
+	},
+	workloadResolver(appsv1.SchemeGroupVersion.WithKind(kindDeployment), "deployments", "deployment", "deploy"),
+	workloadResolver(appsv1.SchemeGroupVersion.WithKind(kindStatefulSet), "statefulsets", "statefulset", "sts"),
+	workloadResolver(appsv1.SchemeGroupVersion.WithKind(kindDaemonSet), "daemonsets", "daemonset", "ds"),
+	workloadResolver(appsv1.SchemeGroupVersion.WithKind(kindReplicaSet), "replicasets", "replicaset", "rs"),
+	workloadResolver(batchv1.SchemeGroupVersion.WithKind(kindJob), "jobs", "job"),
+	{
+		GVK:     corev1.SchemeGroupVersion.WithKind("PersistentVolume"),
+		Aliases: []string{"persistentvolumes", "persistentvolume", "pv"},
+		Scope:   ScopeCluster,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			return single(cp.PersistentVolume(ctx, name))
+		},
+	},
+	{
+		GVK:     corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"),
+		Aliases: []string{"persistentvolumeclaims", "persistentvolumeclaim", "pvc"},
+		Scope:   ScopeNamespaced,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			return single(cp.PersistentVolumeClaim(ctx, namespace, name))
+		},
+		Candidates: claimCandidates,
+	},
+}
+
+func workloadResolver(gvk schema.GroupVersionKind, aliases ...string) Resolver {
+	return Resolver{
+		GVK:     gvk,
+		Aliases: aliases,
+		Scope:   ScopeNamespaced,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			return cp.Workload(ctx, gvk.Kind, namespace, name)
+		},
+	}
+}
+
+// single wraps the result of a CloudPather method that resolves to one link
+func single(link Link, err error) ([]Link, error) {
+	if err != nil {
+		return nil, err
+	}
+	return []Link{link}, nil
+}
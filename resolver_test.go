@@ -0,0 +1,154 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	restclient "k8s.io/client-go/rest"
+)
+
+func Test_registry_register(t *testing.T) {
+	resolve := func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+		return []Link{{Title: "Database " + name}}, nil
+	}
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Database"}
+
+	tests := []struct {
+		name     string
+		resolver Resolver
+		wantErr  bool
+	}{
+		{
+			name:     "new kind",
+			resolver: Resolver{GVK: gvk, Aliases: []string{"databases", "db"}, Resolve: resolve},
+			wantErr:  false,
+		},
+		{
+			name:     "duplicate kind",
+			resolver: Resolver{GVK: builtinResolvers[0].GVK, Aliases: []string{"databases"}, Resolve: resolve},
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate alias",
+			resolver: Resolver{GVK: gvk, Aliases: []string{"svc"}, Resolve: resolve},
+			wantErr:  true,
+		},
+		{
+			name:     "missing alias",
+			resolver: Resolver{GVK: gvk, Resolve: resolve},
+			wantErr:  true,
+		},
+		{
+			name:     "missing resolve function",
+			resolver: Resolver{GVK: gvk, Aliases: []string{"databases"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRegistry()
+			for _, resolver := range builtinResolvers {
+				if err := r.register(resolver); err != nil {
+					t.Fatalf("registry.register() builtin error = %v", err)
+				}
+			}
+
+			err := r.register(tt.resolver)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("registry.register() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			for _, alias := range tt.resolver.Aliases {
+				got := r.lookup(alias)
+				if got == nil || got.GVK != tt.resolver.GVK {
+					t.Errorf("registry.lookup(%s) = %v, want %v", alias, got, tt.resolver.GVK)
+					continue
+				}
+
+				links, _ := got.Resolve(context.TODO(), nil, "", "main", Options{})
+				if !reflect.DeepEqual(titles(links), []string{"Database main"}) {
+					t.Errorf("registry.lookup(%s).Resolve() = %v", alias, links)
+				}
+			}
+		})
+	}
+}
+
+// unregister removes a resolver added by a test
+func (r *registry) unregister(resolver Resolver) {
+	delete(r.byGVK, resolver.GVK)
+	delete(r.byGroupKind, resolver.GVK.GroupKind())
+	for _, alias := range resolver.Aliases {
+		delete(r.byAlias, alias)
+	}
+}
+
+func TestRegister_readsObjects(t *testing.T) {
+	ctx := context.TODO()
+	// backups are resolved the way a resolver registered by another package would,
+	// from a ConfigMap naming the volume the backup is stored on
+	backups := Resolver{
+		GVK:     schema.GroupVersionKind{Group: "backups.example.com", Version: "v1", Kind: "Backup"},
+		Aliases: []string{"backups", "backup"},
+		Scope:   ScopeNamespaced,
+		Resolve: func(ctx context.Context, cp CloudPather, namespace, name string, opts Options) ([]Link, error) {
+			clients := cp.Clients()
+			configMap, err := clients.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, "backup-"+name, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return []Link{{
+				Title: fmt.Sprintf("Backup %s of cluster %s", name, clients.ClusterID),
+				URL:   DefaultCloudBase + "volumes/" + configMap.Data["volume"],
+			}}, nil
+		},
+	}
+	Register(backups)
+	t.Cleanup(func() { resolvers.unregister(backups) })
+
+	cp := newFakeDOCloudPather()
+	cp.clientConfig = &restclient.Config{Host: "https://cluster-id" + hostnameSuffix}
+	cp.clientset.CoreV1().ConfigMaps("backups").Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-nightly"},
+		Data:       map[string]string{"volume": "volume-id"},
+	}, metav1.CreateOptions{})
+
+	client := newClient(cp, nil, ClientOptions{Namespace: "backups"})
+	results := client.Resolve(ctx, Reference{Type: "backup", Name: "nightly"})
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("Client.Resolve() = %v", results)
+	}
+	if got := paths(results[0].Links); !reflect.DeepEqual(got, []string{"volumes/volume-id"}) {
+		t.Errorf("Client.Resolve() = %v, want volumes/volume-id", got)
+	}
+	if got := titles(results[0].Links); !reflect.DeepEqual(got, []string{"Backup nightly of cluster cluster-id"}) {
+		t.Errorf("Client.Resolve() titles = %v", got)
+	}
+
+	results = client.Resolve(ctx, Reference{Type: "backup", Name: "weekly"})
+	if len(results) != 1 || results[0].Err == nil {
+		t.Errorf("Client.Resolve() of a missing backup = %v, want an error", results)
+	}
+}
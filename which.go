@@ -0,0 +1,247 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type WhichRunner func(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, target string, opts RunOptions) ([]Match, error)
+
+// Target is a DigitalOcean resource to find the Kubernetes objects of. An
+// empty Kind stands for any resource with the ID.
+type Target struct {
+	Kind ResourceKind
+	ID   string
+}
+
+// Match is a Kubernetes object backed by, or using, a DigitalOcean resource
+type Match struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Reason tells how the object relates to the resource
+	Reason string
+	// Context is the kube context the object was found in when searching all of them
+	Context string
+}
+
+// ParseTarget parses the control panel URLs kubectl-doweb generates, with or
+// without the host, and bare resource IDs. Numeric IDs are Droplet IDs; other
+// IDs may be of any resource.
+func ParseTarget(s string) (Target, error) {
+	if !strings.Contains(s, "/") {
+		if s == "" {
+			return Target{}, ErrMissingArgument
+		}
+		if strings.Trim(s, "0123456789") == "" {
+			return Target{Kind: ResourceDroplet, ID: s}, nil
+		}
+		return Target{ID: s}, nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return Target{}, err
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch {
+	case len(segments) >= 2 && segments[0] == "droplets":
+		return Target{Kind: ResourceDroplet, ID: segments[1]}, nil
+	case len(segments) >= 3 && segments[0] == "networking" && segments[1] == "load_balancers":
+		return Target{Kind: ResourceLoadBalancer, ID: segments[2]}, nil
+	case len(segments) >= 2 && segments[0] == "volumes":
+		return Target{Kind: ResourceVolume, ID: segments[1]}, nil
+	case len(segments) >= 5 && segments[0] == "kubernetes" && segments[1] == "clusters" && segments[3] == "nodepools":
+		return Target{Kind: ResourceNodePool, ID: segments[4]}, nil
+	case len(segments) >= 3 && segments[0] == "kubernetes" && segments[1] == "clusters":
+		return Target{Kind: ResourceKubernetesCluster, ID: segments[2]}, nil
+	}
+
+	return Target{}, fmt.Errorf("%s is not the URL of a Kubernetes cluster, node pool, Droplet, Load Balancer or Volume", s)
+}
+
+// Which resolves target to a DigitalOcean resource and returns the Kubernetes
+// objects of the cluster behind it, along with the pods and claims using them
+func Which(ctx context.Context, writer io.Writer, kubeConfig clientcmd.ClientConfig, target string, opts RunOptions) ([]Match, error) {
+	t, err := ParseTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.AllContexts {
+		matches, skipped, err := WhichAllContexts(ctx, kubeConfig, t, opts)
+		printSkippedContexts(writer, skipped)
+		return matches, err
+	}
+
+	client, _, err := clientFor(writer, kubeConfig, "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Which(ctx, t)
+}
+
+// Which returns the Kubernetes objects behind target, in every namespace
+func (c *Client) Which(ctx context.Context, target Target) ([]Match, error) {
+	var matches []Match
+	searches := []struct {
+		kind   ResourceKind
+		search func(context.Context, string) ([]Match, error)
+	}{
+		{ResourceKubernetesCluster, c.cp.clusterByID},
+		{ResourceNodePool, c.cp.nodesByPoolID},
+		{ResourceDroplet, c.cp.nodesByDropletID},
+		{ResourceLoadBalancer, c.cp.servicesByLoadBalancerID},
+		{ResourceVolume, c.cp.volumesByID},
+	}
+	for _, s := range searches {
+		if target.Kind != "" && target.Kind != s.kind {
+			continue
+		}
+
+		found, err := s.search(ctx, target.ID)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, found...)
+	}
+
+	return matches, nil
+}
+
+func (cp *DOCloudPather) clusterByID(ctx context.Context, id string) ([]Match, error) {
+	clusterID, err := cp.clusterID()
+	if err != nil || clusterID != id {
+		return nil, nil
+	}
+	return []Match{{Kind: "Cluster", Name: id, Reason: "cluster ID"}}, nil
+}
+
+func (cp *DOCloudPather) nodesByPoolID(ctx context.Context, id string) ([]Match, error) {
+	nodes, err := cp.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", nodePoolIDLabel, id),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, node := range nodes.Items {
+		if node.Labels[nodePoolIDLabel] != id {
+			continue
+		}
+		matches = append(matches, Match{Kind: "Node", Name: node.Name, Reason: fmt.Sprintf("in node pool %s", node.Labels[nodePoolNameLabel])})
+	}
+	return matches, nil
+}
+
+func (cp *DOCloudPather) nodesByDropletID(ctx context.Context, id string) ([]Match, error) {
+	nodes, err := cp.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, node := range nodes.Items {
+		if node.Spec.ProviderID != nodeIDPrefix+id {
+			continue
+		}
+		matches = append(matches, Match{Kind: "Node", Name: node.Name, Reason: fmt.Sprintf("providerID %s", node.Spec.ProviderID)})
+
+		// the fake clientset ignores field selectors, hence the check below
+		pods, err := cp.clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node.Name).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName != node.Name {
+				continue
+			}
+			matches = append(matches, Match{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, Reason: fmt.Sprintf("runs on node %s", node.Name)})
+		}
+	}
+	return matches, nil
+}
+
+func (cp *DOCloudPather) servicesByLoadBalancerID(ctx context.Context, id string) ([]Match, error) {
+	services, err := cp.clientset.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, service := range services.Items {
+		if service.Annotations[lbaasAnnotation] != id {
+			continue
+		}
+		matches = append(matches, Match{Kind: "Service", Namespace: service.Namespace, Name: service.Name, Reason: fmt.Sprintf("annotation %s", lbaasAnnotation)})
+	}
+	return matches, nil
+}
+
+func (cp *DOCloudPather) volumesByID(ctx context.Context, id string) ([]Match, error) {
+	pvs, err := cp.clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, pv := range pvs.Items {
+		csi := pv.Spec.CSI
+		if csi == nil || csi.Driver != csiDriverName || csi.VolumeHandle != id {
+			continue
+		}
+		matches = append(matches, Match{Kind: "PersistentVolume", Name: pv.Name, Reason: "CSI volume handle"})
+
+		claim := pv.Spec.ClaimRef
+		if claim == nil {
+			continue
+		}
+		matches = append(matches, Match{Kind: "PersistentVolumeClaim", Namespace: claim.Namespace, Name: claim.Name, Reason: fmt.Sprintf("bound to PersistentVolume %s", pv.Name)})
+
+		pods, err := cp.clientset.CoreV1().Pods(claim.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods.Items {
+			if usesClaim(pod, claim.Name) {
+				matches = append(matches, Match{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, Reason: fmt.Sprintf("mounts PersistentVolumeClaim %s", claim.Name)})
+			}
+		}
+	}
+	return matches, nil
+}
+
+func usesClaim(pod corev1.Pod, claimName string) bool {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim != nil && vol.PersistentVolumeClaim.ClaimName == claimName {
+			return true
+		}
+	}
+	return false
+}
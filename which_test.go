@@ -0,0 +1,181 @@
+/*
+Copyright 2020 Kamal Nasser All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectldoweb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		want    Target
+		wantErr bool
+	}{
+		{
+			name:   "droplet url",
+			target: "https://cloud.digitalocean.com/droplets/123/graphs",
+			want:   Target{Kind: ResourceDroplet, ID: "123"},
+		},
+		{
+			name:   "load balancer path",
+			target: "networking/load_balancers/lb-id",
+			want:   Target{Kind: ResourceLoadBalancer, ID: "lb-id"},
+		},
+		{
+			name:   "volume url",
+			target: "https://cloud.digitalocean.com/volumes/volume-id",
+			want:   Target{Kind: ResourceVolume, ID: "volume-id"},
+		},
+		{
+			name:   "cluster url",
+			target: "https://cloud.digitalocean.com/kubernetes/clusters/cluster-id/insights",
+			want:   Target{Kind: ResourceKubernetesCluster, ID: "cluster-id"},
+		},
+		{
+			name:   "node pool url",
+			target: "https://cloud.digitalocean.com/kubernetes/clusters/cluster-id/nodepools/pool-id",
+			want:   Target{Kind: ResourceNodePool, ID: "pool-id"},
+		},
+		{
+			name:   "droplet id",
+			target: "123",
+			want:   Target{Kind: ResourceDroplet, ID: "123"},
+		},
+		{
+			name:   "other id",
+			target: "4de7ac8b-495b-4884-9a69-1050c6793cd6",
+			want:   Target{ID: "4de7ac8b-495b-4884-9a69-1050c6793cd6"},
+		},
+		{
+			name:    "volumes list page",
+			target:  "https://cloud.digitalocean.com/volumes",
+			wantErr: true,
+		},
+		{
+			name:    "unknown page",
+			target:  "https://cloud.digitalocean.com/account/billing",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTarget(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTarget() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseTarget() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Which(t *testing.T) {
+	ctx := context.TODO()
+	cp := newFakeDOCloudPather()
+	cp.clientConfig = &restclient.Config{Host: "https://cluster-id" + hostnameSuffix}
+
+	cp.clientset.CoreV1().Nodes().Create(ctx, &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{nodePoolIDLabel: "pool-id", nodePoolNameLabel: "web"}},
+		Spec:       corev1.NodeSpec{ProviderID: nodeIDPrefix + "123"},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Pods("web").Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "web"},
+		Spec: corev1.PodSpec{
+			NodeName: "node-1",
+			Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}},
+			}},
+		},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().Services("web").Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "web", Annotations: map[string]string{lbaasAnnotation: "lb-id"}},
+	}, metav1.CreateOptions{})
+	cp.clientset.CoreV1().PersistentVolumes().Create(ctx, &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: csiDriverName, VolumeHandle: "volume-id"},
+			},
+			ClaimRef: &corev1.ObjectReference{Namespace: "web", Name: "data"},
+		},
+	}, metav1.CreateOptions{})
+	client := newClient(cp, nil, ClientOptions{})
+
+	tests := []struct {
+		name   string
+		target Target
+		want   []Match
+	}{
+		{
+			name:   "droplet",
+			target: Target{Kind: ResourceDroplet, ID: "123"},
+			want: []Match{
+				{Kind: "Node", Name: "node-1", Reason: "providerID digitalocean://123"},
+				{Kind: "Pod", Namespace: "web", Name: "web-1", Reason: "runs on node node-1"},
+			},
+		},
+		{
+			name:   "load balancer id of any kind",
+			target: Target{ID: "lb-id"},
+			want:   []Match{{Kind: "Service", Namespace: "web", Name: "web", Reason: "annotation " + lbaasAnnotation}},
+		},
+		{
+			name:   "volume",
+			target: Target{Kind: ResourceVolume, ID: "volume-id"},
+			want: []Match{
+				{Kind: "PersistentVolume", Name: "pv-1", Reason: "CSI volume handle"},
+				{Kind: "PersistentVolumeClaim", Namespace: "web", Name: "data", Reason: "bound to PersistentVolume pv-1"},
+				{Kind: "Pod", Namespace: "web", Name: "web-1", Reason: "mounts PersistentVolumeClaim data"},
+			},
+		},
+		{
+			name:   "node pool",
+			target: Target{Kind: ResourceNodePool, ID: "pool-id"},
+			want:   []Match{{Kind: "Node", Name: "node-1", Reason: "in node pool web"}},
+		},
+		{
+			name:   "cluster",
+			target: Target{ID: "cluster-id"},
+			want:   []Match{{Kind: "Cluster", Name: "cluster-id", Reason: "cluster ID"}},
+		},
+		{
+			name:   "unknown id",
+			target: Target{ID: "whomst"},
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.Which(ctx, tt.target)
+			if err != nil {
+				t.Errorf("Client.Which() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.Which() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}